@@ -0,0 +1,100 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ansiState is where an ANSIStripper is in the escape sequence it strips.
+type ansiState int
+
+const (
+	ansiText ansiState = iota
+	// ansiEscape follows an ESC.
+	ansiEscape
+	// ansiCSI is in a `ESC [` control sequence, ended by a byte from `@`
+	// to `~`.
+	ansiCSI
+	// ansiString is in a `ESC ]` operating system command, or a `ESC P`,
+	// `ESC _` or `ESC ^` string, ended by BEL or `ESC \`.
+	ansiString
+	// ansiStringEscape follows an ESC in a string, likely ending it.
+	ansiStringEscape
+)
+
+// ANSIStripper writes through to w what it's written but ANSI escape
+// sequences, e.g. colors. Sequences may be split across writes.
+type ANSIStripper struct {
+	w     io.Writer
+	state ansiState
+}
+
+func NewANSIStripper(w io.Writer) *ANSIStripper {
+	return &ANSIStripper{w: w}
+}
+
+// Write strips escape sequences of p, reporting all of p as written when the
+// rest is.
+func (s *ANSIStripper) Write(p []byte) (int, error) {
+	out := make([]byte, 0, len(p))
+	for _, c := range p {
+		switch s.state {
+		case ansiText:
+			if c == 0x1b {
+				s.state = ansiEscape
+			} else {
+				out = append(out, c)
+			}
+		case ansiEscape:
+			switch {
+			case c == '[':
+				s.state = ansiCSI
+			case c == ']' || c == 'P' || c == '_' || c == '^':
+				s.state = ansiString
+			case c == 0x1b:
+				// A lone ESC, the next one starts the sequence.
+			case c >= 0x20 && c <= 0x2f:
+				// Intermediate bytes, e.g. of `ESC ( B` selecting a charset.
+			default:
+				// The final byte, e.g. of `ESC c` resetting the terminal.
+				s.state = ansiText
+			}
+		case ansiCSI:
+			if c >= 0x40 && c <= 0x7e {
+				s.state = ansiText
+			}
+		case ansiString:
+			if c == 0x07 {
+				s.state = ansiText
+			} else if c == 0x1b {
+				s.state = ansiStringEscape
+			}
+		case ansiStringEscape:
+			if c == '\\' {
+				s.state = ansiText
+			} else if c != 0x1b {
+				s.state = ansiString
+			}
+		}
+	}
+
+	if _, err := s.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// StripANSI returns s without its ANSI escape sequences.
+func StripANSI(s string) string {
+	var b strings.Builder
+	NewANSIStripper(&b).Write([]byte(s))
+	return b.String()
+}
+
+// StripANSIFilter copies stdin to stdout without ANSI escape sequences, e.g.
+// to clean up colored output captured earlier.
+func (e *Executor) StripANSIFilter() error {
+	_, err := io.Copy(NewANSIStripper(os.Stdout), os.Stdin)
+	return err
+}
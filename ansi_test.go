@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "git status", "git status"},
+		{"color", "\x1b[1mbold\x1b[0m", "bold"},
+		{"CSI with parameters", "\x1b[38;5;196mred\x1b[39;49m text", "red text"},
+		{"cursor movement", "a\x1b[2Kb\x1b[10;20Hc", "abc"},
+		{"OSC ended by BEL", "\x1b]0;title\x07text", "text"},
+		{"OSC ended by ST", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"DCS string", "\x1bPq#0;2;0;0;0\x1b\\after", "after"},
+		{"charset selection", "\x1b(Btext", "text"},
+		{"two character sequence", "\x1bcreset", "reset"},
+		{"lone ESC before a sequence", "\x1b\x1b[31mred", "red"},
+		{"unterminated CSI", "text\x1b[31", "text"},
+		{"utf-8 kept", "\x1b[32m提交\x1b[0m", "提交"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripANSI(tt.in); got != tt.want {
+				t.Errorf("StripANSI(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestANSIStripperSplitWrites(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   string
+	}{
+		{"split after ESC", []string{"a\x1b", "[31mb"}, "ab"},
+		{"split in parameters", []string{"a\x1b[38;5", ";196mb\x1b[0", "m"}, "ab"},
+		{"split OSC", []string{"\x1b]0;ti", "tle\x1b", "\\text"}, "text"},
+		{"split OSC before BEL", []string{"\x1b]0;title", "\x07text"}, "text"},
+		{"byte by byte", strings.Split("x\x1b[1;31my\x1b]2;t\x07z", ""), "xyz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			s := NewANSIStripper(&b)
+			for _, chunk := range tt.chunks {
+				n, err := s.Write([]byte(chunk))
+				if err != nil {
+					t.Fatalf("Write(%q) failed: %v", chunk, err)
+				}
+				if n != len(chunk) {
+					t.Fatalf("Write(%q) = %d, want %d", chunk, n, len(chunk))
+				}
+			}
+
+			if got := b.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
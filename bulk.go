@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// BulkResult collects the outcome of each item of a bulk operation, so that
+// a failing item doesn't abort the others.
+type BulkResult struct {
+	Succeeded []string
+	Failed    []BulkFailure
+}
+
+type BulkFailure struct {
+	Item string
+	Err  error
+}
+
+// Add records the outcome of the item, failed if err isn't nil.
+func (r *BulkResult) Add(item string, err error) {
+	if err != nil {
+		r.Failed = append(r.Failed, BulkFailure{Item: item, Err: err})
+		return
+	}
+	r.Succeeded = append(r.Succeeded, item)
+}
+
+// Summary writes how many items succeeded and why each failed one failed,
+// returning an ExitError when any did.
+func (r *BulkResult) Summary(w io.Writer, verb string) error {
+	fmt.Fprintf(w, "%v: %d of %d", verb, len(r.Succeeded), len(r.Succeeded)+len(r.Failed))
+	if len(r.Failed) == 0 {
+		fmt.Fprintln(w)
+		return nil
+	}
+
+	fmt.Fprintf(w, ", %d failed:\n", len(r.Failed))
+	for _, failure := range r.Failed {
+		fmt.Fprintf(w, "\t%v: %v\n", failure.Item, failure.Err)
+	}
+	return &ExitError{Code: 1}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// PageSnapshot maps the filenames of a page directory of the tldr cache to
+// the hashes of their content.
+type PageSnapshot map[string][sha256.Size]byte
+
+// SnapshotPage hashes every file of a page directory. A missing directory
+// gives an empty snapshot.
+func SnapshotPage(dirname string) (PageSnapshot, error) {
+	snapshot := make(PageSnapshot)
+	entries, err := os.ReadDir(dirname)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return snapshot, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		sum, err := HashFile(filepath.Join(dirname, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		snapshot[entry.Name()] = sum
+	}
+
+	return snapshot, nil
+}
+
+type PageChanges struct {
+	Added   []string
+	Removed []string
+	Updated []string
+}
+
+// Diff returns the changes from the snapshot to a later one.
+func (s PageSnapshot) Diff(later PageSnapshot) *PageChanges {
+	changes := &PageChanges{}
+	for name, sum := range later {
+		before, ok := s[name]
+		if !ok {
+			changes.Added = append(changes.Added, name)
+		} else if before != sum {
+			changes.Updated = append(changes.Updated, name)
+		}
+	}
+
+	for name := range s {
+		if _, ok := later[name]; !ok {
+			changes.Removed = append(changes.Removed, name)
+		}
+	}
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Updated)
+	return changes
+}
+
+func HashFile(path string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	f, err := os.Open(path)
+	if err != nil {
+		return sum, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return sum, err
+	}
+
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
+
+// DuplicateGroup is a set of identical files of the tldr cache.
+type DuplicateGroup struct {
+	Size  int64
+	Paths []string
+}
+
+// Savings returns the bytes freed by keeping a single copy of the group.
+func (g *DuplicateGroup) Savings() int64 {
+	return g.Size * int64(len(g.Paths)-1)
+}
+
+// FindDuplicates groups identical files across the page directories of the
+// tldr cache. Files already hardlinked together aren't duplicates.
+func FindDuplicates(cachePath string) ([]*DuplicateGroup, error) {
+	pages, err := os.ReadDir(cachePath)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[[sha256.Size]byte]*DuplicateGroup)
+	var order [][sha256.Size]byte
+	for _, page := range pages {
+		if !page.IsDir() {
+			continue
+		}
+
+		pageDir := filepath.Join(cachePath, page.Name())
+		snapshot, err := SnapshotPage(pageDir)
+		if err != nil {
+			return nil, err
+		}
+
+		names := make([]string, 0, len(snapshot))
+		for name := range snapshot {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			path := filepath.Join(pageDir, name)
+			info, err := os.Stat(path)
+			if err != nil {
+				return nil, err
+			}
+
+			sum := snapshot[name]
+			group, ok := groups[sum]
+			if !ok {
+				group = &DuplicateGroup{Size: info.Size()}
+				groups[sum] = group
+				order = append(order, sum)
+			}
+
+			if group.linked(info) {
+				continue
+			}
+			group.Paths = append(group.Paths, path)
+		}
+	}
+
+	var dups []*DuplicateGroup
+	for _, sum := range order {
+		if group := groups[sum]; len(group.Paths) > 1 {
+			dups = append(dups, group)
+		}
+	}
+	return dups, nil
+}
+
+func (g *DuplicateGroup) linked(info os.FileInfo) bool {
+	for _, path := range g.Paths {
+		if other, err := os.Stat(path); err == nil && os.SameFile(info, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hardlink replaces every copy of the group by a hardlink to the first one.
+func (g *DuplicateGroup) Hardlink() error {
+	for _, path := range g.Paths[1:] {
+		tmp := path + ".link"
+		if err := os.Link(g.Paths[0], tmp); err != nil {
+			return err
+		}
+
+		if err := os.Rename(tmp, path); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	return nil
+}
+
+// CachedCheatSheets lists the pages of the tldr cache in the page
+// directories, sorted by topic. A topic in several page directories is
+// listed once, from the first of them like FindFileInCache.
+func CachedCheatSheets(cachePath string, pages []string) ([]CheatSheet, error) {
+	found := make(map[string]bool)
+	var sheets []CheatSheet
+	for _, page := range pages {
+		pageDir := filepath.Join(cachePath, page)
+		entries, err := os.ReadDir(pageDir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || filepath.Ext(name) != "."+DefaultExtension {
+				continue
+			}
+
+			topic := strings.TrimSuffix(name, "."+DefaultExtension)
+			if found[topic] {
+				continue
+			}
+
+			found[topic] = true
+			sheets = append(sheets, CheatSheet{Topic: topic, Path: filepath.Join(pageDir, name)})
+		}
+	}
+
+	sort.Slice(sheets, func(i, j int) bool {
+		return sheets[i].Topic < sheets[j].Topic
+	})
+	return sheets, nil
+}
+
+// NewestModTime returns the latest modification time of the files under the
+// directory, zero when there are none.
+func NewestModTime(dirname string) (time.Time, error) {
+	var newest time.Time
+	err := filepath.WalkDir(dirname, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+		return nil
+	})
+	return newest, err
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var ErrNoClipboard = errors.New("no clipboard tool found, install one of pbcopy, wl-copy, xclip, xsel or clip")
+
+// ClipboardCommand returns the command line copying its stdin to the system
+// clipboard, picked from the tools available on this OS.
+func ClipboardCommand() ([]string, error) {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		candidates = append(candidates,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"},
+			[]string{"wl-copy"},
+		)
+	}
+
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err == nil {
+			return candidate, nil
+		}
+	}
+	return nil, ErrNoClipboard
+}
+
+// CopyToClipboard copies the text to the system clipboard.
+func CopyToClipboard(text string) error {
+	args, err := ClipboardCommand()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
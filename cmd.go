@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -9,14 +11,29 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	_ "embed"
+
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
 )
 
 //go:embed version.txt
 var version string
 
+var ErrNotFound = errors.New("cheat-sheet not found")
+
+// ErrEmptyTopic is returned for a cheat-sheet name that is blank, which
+// would otherwise make a file named only by its extension.
+var ErrEmptyTopic = errors.New("cheat-sheet name is empty")
+
 type CmdKind int
 
 const (
@@ -25,10 +42,106 @@ const (
 	CmdFind
 	CmdEdit
 	CmdUpdate
+	CmdBrowse
+	CmdList
+	CmdPrintConfig
+	CmdViewInEditor
+	CmdStats
+	CmdMerge
+	CmdDedupeCache
+	CmdPeek
+	CmdUsage
+	CmdFindMulti
+	CmdExport
+	CmdClipboard
+	CmdCheck
+	CmdBuildSite
+	CmdWatchUpdate
+	CmdRandom
+	CmdEditConfig
+	CmdSearch
+	CmdResolveOnly
+	CmdGrepCache
+	CmdListProfiles
+	CmdVersionCheck
+	CmdAdopt
+	CmdRaw
+	CmdNoRender
+	CmdRenameAll
+	CmdPrintExamples
+	CmdEncrypt
+	CmdJSONStream
+	CmdPreview
+	CmdShadows
+	CmdOpenURL
+	CmdSetup
+	CmdExplain
+	CmdPick
+	CmdPruneBackups
+	CmdDiffCache
+	CmdWatchDir
+	CmdCheckUpdate
+	CmdTopics
+	CmdSectionList
+	CmdEditTemplate
+	CmdStripANSI
+	CmdImportURL
+	CmdBatchRender
 )
 
 func (c CmdKind) String() string {
-	return []string{"help", "version", "find", "edit", "update"}[c]
+	return []string{
+		"help",
+		"version",
+		"find",
+		"edit",
+		"update",
+		"browse",
+		"list",
+		"print-config",
+		"view-in-editor",
+		"stats",
+		"merge",
+		"dedupe-cache",
+		"peek",
+		"usage",
+		"find-multi",
+		"export",
+		"clipboard",
+		"check",
+		"build-site",
+		"watch-update",
+		"random",
+		"edit-config",
+		"search",
+		"resolve-only",
+		"grep-cache",
+		"list-profiles",
+		"version-check",
+		"adopt",
+		"raw",
+		"no-render",
+		"rename-all",
+		"print-examples",
+		"encrypt",
+		"json-stream",
+		"preview",
+		"shadows",
+		"open-url",
+		"setup",
+		"explain",
+		"pick",
+		"prune-backups",
+		"diff-cache",
+		"watch-dir",
+		"check-update",
+		"topics",
+		"section-list",
+		"edit-template",
+		"strip-ansi",
+		"import-url",
+		"batch-render",
+	}[c]
 }
 
 func CreateCommand(fs *flag.FlagSet) *Command {
@@ -41,6 +154,42 @@ func CreateCommand(fs *flag.FlagSet) *Command {
 		return func(c *Command) {}
 	}
 
+	withBoolFlag := func(name string) CmdOption {
+		boolFlag := fs.Lookup(name)
+		if boolFlag.Value.String() == "true" {
+			return WithFlag(name, "true")
+		}
+
+		return func(c *Command) {}
+	}
+
+	withStringFlag := func(name string) CmdOption {
+		stringFlag := fs.Lookup(name)
+		if val := stringFlag.Value.String(); val != "" {
+			return WithFlag(name, val)
+		}
+
+		return func(c *Command) {}
+	}
+
+	// isSet tells whether the flag is given, even with an empty value.
+	isSet := func(name string) bool {
+		set := false
+		fs.Visit(func(f *flag.Flag) {
+			set = set || f.Name == name
+		})
+		return set
+	}
+
+	// withSetFlag passes the flag when given, even with an empty value.
+	withSetFlag := func(name string) CmdOption {
+		if isSet(name) {
+			return WithFlag(name, fs.Lookup(name).Value.String())
+		}
+
+		return func(c *Command) {}
+	}
+
 	helpFlag := fs.Lookup(HelpFlag)
 	if helpFlag.Value.String() == "true" {
 		return NewCommand(CmdHelp, withLog())
@@ -51,18 +200,285 @@ func CreateCommand(fs *flag.FlagSet) *Command {
 		return NewCommand(CmdVersion, withLog())
 	}
 
+	versionCheckFlag := fs.Lookup(VersionCheckFlag)
+	if versionCheckFlag.Value.String() == "true" {
+		return NewCommand(CmdVersionCheck, withBoolFlag(ISOFlag), withLog())
+	}
+
+	checkUpdateFlag := fs.Lookup(CheckUpdateFlag)
+	if checkUpdateFlag.Value.String() == "true" {
+		return NewCommand(CmdCheckUpdate, withBoolFlag(OfflineFlag), withLog())
+	}
+
+	checkFlag := fs.Lookup(CheckFlag)
+	if checkFlag.Value.String() == "true" {
+		return NewCommand(CmdCheck, withLog())
+	}
+
+	setupFlag := fs.Lookup(SetupFlag)
+	if setupFlag.Value.String() == "true" {
+		return NewCommand(CmdSetup, withLog())
+	}
+
+	editConfigFlag := fs.Lookup(EditConfigFlag)
+	if editConfigFlag.Value.String() == "true" {
+		return NewCommand(CmdEditConfig, withBoolFlag(StrictEditorFlag), withLog())
+	}
+
+	listProfilesFlag := fs.Lookup(ListProfilesFlag)
+	if listProfilesFlag.Value.String() == "true" {
+		return NewCommand(CmdListProfiles, withLog())
+	}
+
+	printConfigFlag := fs.Lookup(PrintConfigFlag)
+	if printConfigFlag.Value.String() == "true" {
+		return NewCommand(CmdPrintConfig, withLog())
+	}
+
+	statsFlag := fs.Lookup(StatsFlag)
+	if statsFlag.Value.String() == "true" {
+		return NewCommand(CmdStats, withBoolFlag(JSONFlag), withStringFlag(FormatFlag), withLog())
+	}
+
+	dedupeCacheFlag := fs.Lookup(DedupeCacheFlag)
+	if dedupeCacheFlag.Value.String() == "true" {
+		return NewCommand(CmdDedupeCache, withBoolFlag(HardlinkFlag), withLog())
+	}
+
+	usageFlag := fs.Lookup(UsageFlag)
+	if usageFlag.Value.String() == "true" {
+		return NewCommand(CmdUsage, withBoolFlag(ResetFlag), withBoolFlag(ReverseFlag), withBoolFlag(ISOFlag), withLog())
+	}
+
+	exportFlag := fs.Lookup(ExportFlag)
+	if exportFlag.Value.String() == "true" {
+		return NewCommand(CmdExport, withSetFlag(SeparatorFlag), withSetFlag(HeaderFlag),
+			withStringFlag(TopicsFileFlag), withLog())
+	}
+
 	updateFlag := fs.Lookup(UpdateFlag)
 	if updateFlag.Value.String() == "true" {
-		return NewCommand(CmdUpdate, withLog())
+		return NewCommand(CmdUpdate, withStringFlag(PageFlag), withLog())
+	}
+
+	browseFlag := fs.Lookup(BrowseFlag)
+	if browseFlag.Value.String() == "true" {
+		return NewCommand(CmdBrowse, withBoolFlag(NoSubstFlag), withBoolFlag(ShowNotesFlag), withStringFlag(ColorFlag),
+			withStringFlag(TopicsFileFlag), withLog())
+	}
+
+	longListFlag := fs.Lookup(LongListFlag)
+	if longListFlag.Value.String() == "true" {
+		return NewCommand(CmdList, WithFlag(LongFlag, "true"), withBoolFlag(ReverseFlag), withStringFlag(SourceFlag), withLog())
+	}
+
+	listFlag := fs.Lookup(ListFlag)
+	if listFlag.Value.String() == "true" {
+		return NewCommand(CmdList, withBoolFlag(LongFlag), withBoolFlag(ReverseFlag), withStringFlag(SourceFlag), withLog())
+	}
+
+	stripANSIFlag := fs.Lookup(StripANSIFlag)
+	if stripANSIFlag.Value.String() == "true" {
+		return NewCommand(CmdStripANSI, withLog())
+	}
+
+	editTemplateFlag := fs.Lookup(EditTemplateFlag)
+	if editTemplateFlag.Value.String() == "true" {
+		return NewCommand(CmdEditTemplate, WithArgs(fs.Args()), withBoolFlag(StrictEditorFlag), withLog())
 	}
 
 	editFlag := fs.Lookup(EditFlag)
-	if val := editFlag.Value.String(); val != "" {
+	if val := editFlag.Value.String(); val != "" || isSet(EditFlag) {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdEdit, WithArgs(args), withStringFlag(FromFlag), withBoolFlag(ForceFlag),
+			withStringFlag(GotoFlag), withBoolFlag(PreserveMtimeFlag), withStringFlag(TemplateFlag),
+			withStringFlag(PageFlag), withBoolFlag(StrictEditorFlag), withLog())
+	}
+
+	importURLFlag := fs.Lookup(ImportURLFlag)
+	if val := importURLFlag.Value.String(); val != "" || isSet(ImportURLFlag) {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdImportURL, WithArgs(args), withBoolFlag(ForceFlag), withBoolFlag(OfflineFlag), withLog())
+	}
+
+	adoptFlag := fs.Lookup(AdoptFlag)
+	if val := adoptFlag.Value.String(); val != "" || isSet(AdoptFlag) {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdAdopt, WithArgs(args), withBoolFlag(ForceFlag), withBoolFlag(PreserveMtimeFlag),
+			withStringFlag(PageFlag), withLog())
+	}
+
+	encryptFlag := fs.Lookup(EncryptFlag)
+	if val := encryptFlag.Value.String(); val != "" || isSet(EncryptFlag) {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdEncrypt, WithArgs(args), withLog())
+	}
+
+	renameAllFlag := fs.Lookup(RenameAllFlag)
+	if val := renameAllFlag.Value.String(); val != "" {
+		return NewCommand(CmdRenameAll, WithArgs([]string{val}), withLog())
+	}
+
+	mergeFlag := fs.Lookup(MergeFlag)
+	if val := mergeFlag.Value.String(); val != "" || isSet(MergeFlag) {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdMerge, WithArgs(args),
+			withBoolFlag(DedupeFlag), withBoolFlag(DeleteSourceFlag), withBoolFlag(ForceFlag), withLog())
+	}
+
+	topicsFlag := fs.Lookup(TopicsFlag)
+	if topicsFlag.Value.String() == "true" {
+		return NewCommand(CmdTopics, withLog())
+	}
+
+	shadowsFlag := fs.Lookup(ShadowsFlag)
+	if shadowsFlag.Value.String() == "true" {
+		return NewCommand(CmdShadows, withLog())
+	}
+
+	jsonStreamFlag := fs.Lookup(JSONStreamFlag)
+	if jsonStreamFlag.Value.String() == "true" {
+		return NewCommand(CmdJSONStream, withLog())
+	}
+
+	previewFlag := fs.Lookup(PreviewFlag)
+	if val := previewFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdPreview, WithArgs(args), withBoolFlag(NoSubstFlag), withBoolFlag(ShowNotesFlag),
+			withStringFlag(ColorFlag), withLog())
+	}
+
+	sectionListFlag := fs.Lookup(SectionListFlag)
+	if val := sectionListFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdSectionList, WithArgs(args), withLog())
+	}
+
+	peekFlag := fs.Lookup(PeekFlag)
+	if val := peekFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdPeek, WithArgs(args), withLog())
+	}
+
+	resolveOnlyFlag := fs.Lookup(ResolveOnlyFlag)
+	if val := resolveOnlyFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdResolveOnly, WithArgs(args), withBoolFlag(StripFrontMatterFlag), withLog())
+	}
+
+	rawFlag := fs.Lookup(RawFlag)
+	if val := rawFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdRaw, WithArgs(args), withBoolFlag(StripFrontMatterFlag), withLog())
+	}
+
+	noRenderFlag := fs.Lookup(NoRenderFlag)
+	if val := noRenderFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdNoRender, WithArgs(args), withBoolFlag(StripFrontMatterFlag), withLog())
+	}
+
+	printExamplesFlag := fs.Lookup(PrintExamplesFlag)
+	if val := printExamplesFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdPrintExamples, WithArgs(args), withStringFlag(ExampleFormatFlag), withLog())
+	}
+
+	clipboardFlag := fs.Lookup(ClipboardFlag)
+	if val := clipboardFlag.Value.String(); val != "" {
 		args := append([]string{val}, fs.Args()...)
-		return NewCommand(CmdEdit, WithArgs(args), withLog())
+		return NewCommand(CmdClipboard, WithArgs(args), withStringFlag(ExampleFormatFlag), withLog())
+	}
+
+	explainFlag := fs.Lookup(ExplainFlag)
+	if val := explainFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdExplain, WithArgs(args), withStringFlag(LangFlag), withBoolFlag(StrictLangFlag),
+			withBoolFlag(NoSubstFlag), withBoolFlag(OfflineFlag), withLog())
+	}
+
+	openURLFlag := fs.Lookup(OpenURLFlag)
+	if val := openURLFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdOpenURL, WithArgs(args), withStringFlag(LangFlag), withBoolFlag(PrintURLFlag), withLog())
+	}
+
+	diffCacheFlag := fs.Lookup(DiffCacheFlag)
+	if val := diffCacheFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdDiffCache, WithArgs(args), withLog())
+	}
+
+	pruneBackupsFlag := fs.Lookup(PruneBackupsFlag)
+	if pruneBackupsFlag.Value.String() == "true" {
+		return NewCommand(CmdPruneBackups, withStringFlag(KeepFlag), withStringFlag(OlderThanFlag), withLog())
+	}
+
+	pickFlag := fs.Lookup(PickFlag)
+	if pickFlag.Value.String() == "true" {
+		return NewCommand(CmdPick, withBoolFlag(NoSubstFlag), withBoolFlag(ShowNotesFlag), withBoolFlag(OfflineFlag),
+			withLog())
+	}
+
+	randomFlag := fs.Lookup(RandomFlag)
+	if randomFlag.Value.String() == "true" {
+		return NewCommand(CmdRandom, withBoolFlag(AllFlag), withStringFlag(TagFlag),
+			withBoolFlag(NoSubstFlag), withBoolFlag(ShowNotesFlag), withLog())
+	}
+
+	watchUpdateFlag := fs.Lookup(WatchUpdateFlag)
+	if val := watchUpdateFlag.Value.String(); val != "" {
+		return NewCommand(CmdWatchUpdate, WithArgs([]string{val}), withLog())
+	}
+
+	watchDirFlag := fs.Lookup(WatchDirFlag)
+	if val := watchDirFlag.Value.String(); val != "" {
+		return NewCommand(CmdWatchDir, WithArgs([]string{val}), withLog())
+	}
+
+	searchFlag := fs.Lookup(SearchFlag)
+	if val := searchFlag.Value.String(); val != "" {
+		return NewCommand(CmdSearch, WithArgs([]string{val}), withBoolFlag(RegexFlag),
+			withStringFlag(LimitFlag), withStringFlag(ColorFlag), withBoolFlag(ReverseFlag), withLog())
+	}
+
+	grepCacheFlag := fs.Lookup(GrepCacheFlag)
+	if val := grepCacheFlag.Value.String(); val != "" {
+		return NewCommand(CmdGrepCache, WithArgs([]string{val}), withBoolFlag(RegexFlag),
+			withStringFlag(LimitFlag), withStringFlag(ColorFlag), withBoolFlag(ReverseFlag), withLog())
+	}
+
+	batchRenderFlag := fs.Lookup(BatchRenderFlag)
+	if batchRenderFlag.Value.String() == "true" {
+		return NewCommand(CmdBatchRender, withStringFlag(OutFlag), withStringFlag(FormatFlag),
+			withBoolFlag(NoSubstFlag), withBoolFlag(ShowNotesFlag), withLog())
+	}
+
+	buildSiteFlag := fs.Lookup(BuildSiteFlag)
+	if val := buildSiteFlag.Value.String(); val != "" {
+		return NewCommand(CmdBuildSite, WithArgs([]string{val}), withBoolFlag(NoSubstFlag),
+			withBoolFlag(ShowNotesFlag), withLog())
+	}
+
+	viewFlag := fs.Lookup(ViewInEditorFlag)
+	if val := viewFlag.Value.String(); val != "" {
+		args := append([]string{val}, fs.Args()...)
+		return NewCommand(CmdViewInEditor, WithArgs(args), withLog())
+	}
+
+	multiFlag := fs.Lookup(MultiFlag)
+	if multiFlag.Value.String() == "true" {
+		return NewCommand(CmdFindMulti, WithArgs(fs.Args()), withBoolFlag(NoSubstFlag), withBoolFlag(ShowNotesFlag),
+			withStringFlag(ColorFlag), withSetFlag(SeparatorFlag), withSetFlag(HeaderFlag), withStringFlag(TopicsFileFlag),
+			withLog())
 	}
 
-	return NewCommand(CmdFind, WithArgs(fs.Args()), withLog())
+	return NewCommand(CmdFind, WithArgs(fs.Args()),
+		withStringFlag(LangFlag), withBoolFlag(StrictLangFlag), withBoolFlag(OfflineFlag),
+		withBoolFlag(NoSubstFlag), withBoolFlag(ShowNotesFlag), withBoolFlag(NumberedFlag), withSetFlag(OnlyFlag),
+		withStringFlag(ColorFlag), withStringFlag(MinMatchFlag), withBoolFlag(PlainFlag),
+		withStringFlag(SourceFlag), withBoolFlag(AllFlag), withSetFlag(SeparatorFlag), withSetFlag(HeaderFlag),
+		withSetFlag(HeadFlag), withSetFlag(TailFlag), withLog())
 }
 
 type CmdOption func(*Command)
@@ -99,156 +515,450 @@ type Command struct {
 }
 
 func (c *Command) PrintLog() bool {
-	_, ok := c.Flags[LogFlag]
-	return ok
+	return c.HasFlag(LogFlag)
 }
 
-func (c *Command) Filename() string {
-	return strings.Join(c.Args, "-") + ".md"
+func (c *Command) HasFlag(name string) bool {
+	_, ok := c.Flags[name]
+	return ok
 }
 
-func DefaultConfig() (*Config, error) {
-	dirname, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
+// IntFlag returns the value of an integer flag, or def when not given.
+func (c *Command) IntFlag(name string, def int) (int, error) {
+	val, ok := c.Flags[name]
+	if !ok {
+		return def, nil
 	}
 
-	cheatSheetDir := filepath.Join(dirname, ".cheat-sheet")
-	_, err = os.Stat(cheatSheetDir)
+	n, err := strconv.Atoi(val)
 	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			return nil, err
-		}
+		return 0, fmt.Errorf("invalid -%v '%v': %w", name, val, err)
+	}
+	return n, nil
+}
 
-		if err = os.Mkdir(cheatSheetDir, 0755); err != nil {
-			return nil, err
-		}
+// Topic returns the normalized topic of the arguments, see NormalizeTopic.
+func (c *Command) Topic() string {
+	return NormalizeTopic(c.Args...)
+}
+
+// requireTopic errors with the usage of the command when its topic is blank.
+func requireTopic(cmd *Command, usage string) error {
+	if cmd.Topic() == "" {
+		return fmt.Errorf("%w, usage: %v", ErrEmptyTopic, usage)
 	}
+	return nil
+}
 
-	tldrCachePath := filepath.Join(dirname, ".tldr/cache/pages")
-	return &Config{
-		CheatSheetsDir: cheatSheetDir,
-		TldrPath:       "tldr",
-		TldrCachePath:  tldrCachePath,
-		TldrPages:      []string{"common", "linux"},
-		EditorPath:     "vim",
-	}, nil
+// NormalizeTopic maps the words of a topic to the name of its page the way
+// tldr does: lower case and joined by dashes, so `git commit`, `git-commit`
+// and `"git commit"` are all `git-commit`.
+func NormalizeTopic(words ...string) string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.Join(words, " ")), "-"))
 }
 
-type Config struct {
-	CheatSheetsDir string
-	TldrPath       string
-	TldrCachePath  string
-	TldrPages      []string
-	EditorPath     string
+func (c *Command) Filename(ext string) string {
+	return c.Topic() + "." + ext
+}
+
+// TldrClient is the tldr client used by the Executor. Tldr shells out to a
+// tldr binary, while a fake can be given with WithTldr, e.g. in tests.
+type TldrClient interface {
+	Find(ctx context.Context, args ...string) error
+	// FindPage is like Find but also reports whether the page was found.
+	FindPage(ctx context.Context, args ...string) (bool, error)
+	Render(ctx context.Context, path string) error
+	Update(ctx context.Context) error
+	// UpdatePage updates only the pages of the page set, e.g. linux, if the
+	// client can, reporting whether it did.
+	UpdatePage(ctx context.Context, page string) (bool, error)
+	Version(ctx context.Context) (string, error)
+	FindFileInCache(filename string) (string, error)
+	FindLocalizedFileInCache(lang, filename string) (string, error)
+	SetPrintLog(printLog bool)
+	// SetStdout makes the client write pages to w instead of stdout.
+	SetStdout(w io.Writer)
 }
 
 func NewTldr(cmdPath, cachePath string, pages []string) *Tldr {
 	return &Tldr{
-		CmdPath:   cmdPath,
-		CachePath: cachePath,
-		pages:     pages,
+		CmdPath:      cmdPath,
+		CachePath:    cachePath,
+		NotFoundCode: DefaultTldrNotFoundCode,
+		pages:        pages,
 	}
 }
 
 type Tldr struct {
 	CmdPath   string
 	CachePath string
-	pages     []string
+	PrintLog  bool
+	// ExtraArgs are appended to every run of tldr, for features specific to
+	// the installed client.
+	ExtraArgs []string
+	// NotFoundCode is the exit code of tldr for pages it doesn't find.
+	NotFoundCode int
+	// Stdout is where tldr writes, stdout if nil.
+	Stdout io.Writer
+	pages  []string
+}
+
+func (t *Tldr) SetPrintLog(printLog bool) {
+	t.PrintLog = printLog
 }
 
-func (t *Tldr) run(args ...string) error {
-	cmd := exec.Command(t.CmdPath, args...)
+func (t *Tldr) SetStdout(w io.Writer) {
+	t.Stdout = w
+}
+
+func (t *Tldr) run(ctx context.Context, args ...string) error {
+	_, err := t.runFound(ctx, args...)
+	return err
+}
+
+// runFound runs tldr, reporting whether the page was found. It's killed when
+// the context is done.
+func (t *Tldr) runFound(ctx context.Context, args ...string) (bool, error) {
+	// Extra args go first, as topic args may follow `--`.
+	args = append(append([]string{}, t.ExtraArgs...), args...)
+	if t.PrintLog {
+		log.Printf("run '%v %v'\n", t.CmdPath, strings.Join(args, " "))
+	}
+
+	cmd := exec.CommandContext(ctx, t.CmdPath, args...)
 	cmd.Stdout = os.Stdout
+	if t.Stdout != nil {
+		cmd.Stdout = t.Stdout
+	}
 	cmd.Stderr = os.Stderr
 
 	err := cmd.Run()
 	if err != nil {
-		// If cheat-sheet not found, tldr exits with code 3.
+		// If cheat-sheet not found, tldr exits with code 3, tealdeer with 1.
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			exitCode := exitErr.ExitCode()
-			if exitCode == 3 {
-				return nil
+			if exitCode == t.NotFoundCode {
+				return false, nil
 			}
 		}
 
-		return err
+		return false, err
 	}
-	return nil
+	return true, nil
+}
+
+func (t *Tldr) Find(ctx context.Context, args ...string) error {
+	return t.run(ctx, topicArgs(args)...)
+}
+
+// FindPage is like Find but also reports whether tldr found the page.
+func (t *Tldr) FindPage(ctx context.Context, args ...string) (bool, error) {
+	return t.runFound(ctx, topicArgs(args)...)
 }
 
-func (t *Tldr) Find(args ...string) error {
-	return t.run(args...)
+// topicArgs passes topics starting with a dash, given after `--`, to tldr
+// after `--` too so it doesn't take them for options.
+func topicArgs(args []string) []string {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return append([]string{"--"}, args...)
+		}
+	}
+	return args
 }
 
-func (t *Tldr) Render(path string) error {
+func (t *Tldr) Render(ctx context.Context, path string) error {
 	args := []string{"--render", path}
-	return t.run(args...)
+	return t.run(ctx, args...)
+}
+
+func (t *Tldr) Update(ctx context.Context) error {
+	return t.run(ctx, "--update")
+}
+
+// UpdatePage runs `--update --platform <page>` when the client supports it,
+// as probed from its help.
+func (t *Tldr) UpdatePage(ctx context.Context, page string) (bool, error) {
+	ok, err := t.canUpdatePage(ctx)
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, t.run(ctx, "--update", "--platform", page)
+}
+
+// canUpdatePage probes the client with `--help`. A client failing to print
+// its help is taken as not supporting it.
+func (t *Tldr) canUpdatePage(ctx context.Context) (bool, error) {
+	output, err := exec.CommandContext(ctx, t.CmdPath, "--help").CombinedOutput()
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	ok := err == nil && CanUpdatePage(string(output))
+	if t.PrintLog {
+		log.Printf("tldr client can update a single page set: %v\n", ok)
+	}
+	return ok, nil
 }
 
-func (t *Tldr) Update() error {
-	return t.run("--update")
+var platformOptionPattern = regexp.MustCompile(`(^|[\s,])--platform\b`)
+
+// CanUpdatePage reports whether the help of a tldr client lists both an
+// `--update` and a `--platform` option, so that it can be told to update the
+// pages of a single page set.
+func CanUpdatePage(help string) bool {
+	return strings.Contains(help, "--update") && platformOptionPattern.MatchString(help)
 }
 
+// FindFileInCache returns the path of the file in the first page directory
+// of the tldr cache having it, or an empty string if none has it.
 func (t *Tldr) FindFileInCache(filename string) (string, error) {
+	return t.findFileInCache(t.CachePath, filename)
+}
+
+// FindLocalizedFileInCache is like FindFileInCache but looks at the pages
+// translated to the given language, stored next to the english ones with
+// the language as suffix, e.g. `pages.zh`.
+func (t *Tldr) FindLocalizedFileInCache(lang, filename string) (string, error) {
+	// The english pages of tealdeer are `pages.en`.
+	return t.findFileInCache(strings.TrimSuffix(t.CachePath, ".en")+"."+lang, filename)
+}
+
+func (t *Tldr) findFileInCache(cachePath, filename string) (string, error) {
 	var dirs []string
 	for _, page := range t.pages {
-		dirs = append(dirs, filepath.Join(t.CachePath, page))
+		dirs = append(dirs, filepath.Join(cachePath, page))
 	}
 
 	for _, dir := range dirs {
-		ok, err := IsFileExists(dir, filename)
+		name, err := FindFileFold(dir, filename)
 		if err != nil {
 			return "", err
 		}
 
-		if ok {
-			return dir, nil
+		if name == "" {
+			continue
 		}
+
+		// Skip broken pages so they don't mask the ones of the next dirs.
+		path := filepath.Join(dir, name)
+		if err := CheckReadable(path); err != nil {
+			if t.PrintLog {
+				log.Printf("skip cache page: %v\n", err)
+			}
+			continue
+		}
+
+		return path, nil
 	}
 
 	return "", nil
 }
 
-func (t *Tldr) Version() (string, error) {
-	cmd := exec.Command(t.CmdPath, "--version")
+func (t *Tldr) Version(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, t.CmdPath, "--version")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
 	}
 
-	return strings.TrimSpace(string(output)), err
+	return ParseVersion(string(output)), nil
+}
+
+var versionPattern = regexp.MustCompile(`\bv?(\d+\.\d+(\.\d+)?(-[0-9A-Za-z.]+)?)\b`)
+
+// ParseVersion picks the version out of the `--version` output of a tldr
+// client, which differs between clients, e.g. `tealdeer 1.6.1` or
+// `v3.3.0` then the version of the spec. It's the first semver-looking
+// token without its `v`, or else the first non-blank line.
+func ParseVersion(output string) string {
+	if m := versionPattern.FindStringSubmatch(output); m != nil {
+		return m[1]
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+type ExecutorOption func(*Executor)
+
+// WithTldr makes the executor use the given tldr client rather than the
+// configured tldr binary.
+func WithTldr(tldr TldrClient) ExecutorOption {
+	return func(e *Executor) {
+		e.tldr = tldr
+	}
 }
 
-func NewExecutor(cfg *Config) *Executor {
-	return &Executor{
+func NewExecutor(cfg *Config, opts ...ExecutorOption) *Executor {
+	tldr := NewTldr(cfg.TldrPath, cfg.TldrCachePath, cfg.TldrPages)
+	tldr.ExtraArgs = cfg.TldrArgs
+	if cfg.TldrNotFoundCode != 0 {
+		tldr.NotFoundCode = cfg.TldrNotFoundCode
+	}
+	e := &Executor{
+		ctx:  context.Background(),
 		cfg:  cfg,
-		tldr: NewTldr(cfg.TldrPath, cfg.TldrCachePath, cfg.TldrPages),
+		tldr: tldr,
 	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
 }
 
 type Executor struct {
-	cfg  *Config
-	tldr *Tldr
+	// ctx of the command executed, cancelling the processes it runs.
+	ctx      context.Context
+	cfg      *Config
+	tldr     TldrClient
+	printLog bool
+	// color is the color mode of the command, see UseColor.
+	color string
+	// batPath is the bat binary, looked up once when first rendering with it.
+	batPath    string
+	batChecked bool
+	// cachedPassphrase of encrypted cheat-sheets, prompted once.
+	cachedPassphrase string
+	// plain output is for scripts: natively rendered without color, no
+	// prompts, and an error when not found.
+	plain bool
 }
 
-func (e *Executor) Exec(cmd *Command) error {
-	var err error
+// Exec executes the command, the processes it runs being killed once the
+// context is done.
+func (e *Executor) Exec(ctx context.Context, cmd *Command) error {
+	e.ctx = ctx
+	e.printLog = cmd.PrintLog()
+	e.tldr.SetPrintLog(e.printLog)
+	e.color = cmd.Flags[ColorFlag]
+	restoreOutput, err := e.limitOutput(cmd)
+	if err != nil {
+		return err
+	}
+	if e.color == ColorNever {
+		// Clients color their output even when asked not to.
+		e.tldr.SetStdout(NewANSIStripper(os.Stdout))
+	}
+	if e.plain = cmd.HasFlag(PlainFlag); e.plain {
+		e.cfg.Renderer = RendererNative
+	}
+
 	switch cmd.Cmd {
 	case CmdHelp:
 		e.PrintHelp()
 	case CmdVersion:
 		err = e.PrintVersion()
 	case CmdFind:
-		err = e.Find(cmd)
+		if err = e.Find(cmd); err == nil {
+			e.recordUsage(cmd)
+		}
 	case CmdUpdate:
 		err = e.Update(cmd)
 	case CmdEdit:
 		err = e.Edit(cmd)
+	case CmdBrowse:
+		err = e.Browse(cmd)
+	case CmdList:
+		err = e.List(cmd)
+	case CmdPrintConfig:
+		err = e.PrintConfig()
+	case CmdViewInEditor:
+		err = e.ViewInEditor(cmd)
+	case CmdStats:
+		err = e.Stats(cmd)
+	case CmdMerge:
+		err = e.Merge(cmd)
+	case CmdDedupeCache:
+		err = e.DedupeCache(cmd)
+	case CmdPeek:
+		err = e.Peek(cmd)
+	case CmdSectionList:
+		err = e.SectionList(cmd)
+	case CmdEditTemplate:
+		err = e.EditTemplate(cmd)
+	case CmdStripANSI:
+		err = e.StripANSIFilter()
+	case CmdImportURL:
+		err = e.ImportURL(cmd)
+	case CmdBatchRender:
+		err = e.BatchRender(cmd)
+	case CmdUsage:
+		err = e.Usage(cmd)
+	case CmdFindMulti:
+		err = e.FindMulti(cmd)
+	case CmdExport:
+		err = e.Export(cmd)
+	case CmdClipboard:
+		err = e.Clipboard(cmd)
+	case CmdCheck:
+		err = e.Check()
+	case CmdBuildSite:
+		err = e.BuildSite(cmd)
+	case CmdWatchUpdate:
+		err = e.WatchUpdate(cmd)
+	case CmdWatchDir:
+		err = e.WatchDir(cmd)
+	case CmdRandom:
+		err = e.Random(cmd)
+	case CmdEditConfig:
+		err = e.EditConfig(cmd)
+	case CmdSearch:
+		err = e.Search(cmd)
+	case CmdResolveOnly:
+		err = e.ResolveOnly(cmd)
+	case CmdGrepCache:
+		err = e.GrepCache(cmd)
+	case CmdListProfiles:
+		err = e.ListProfiles()
+	case CmdVersionCheck:
+		err = e.VersionCheck(cmd)
+	case CmdCheckUpdate:
+		err = e.CheckUpdate(cmd)
+	case CmdAdopt:
+		err = e.Adopt(cmd)
+	case CmdRaw:
+		err = e.printMarkdown(cmd, cmd.HasFlag(StripFrontMatterFlag))
+	case CmdDiffCache:
+		err = e.DiffCache(cmd)
+	case CmdPruneBackups:
+		err = e.PruneBackups(cmd)
+	case CmdPick:
+		err = e.PickTopic(cmd)
+	case CmdExplain:
+		err = e.Explain(cmd)
+	case CmdSetup:
+		err = e.Setup()
+	case CmdOpenURL:
+		err = e.OpenURL(cmd)
+	case CmdTopics:
+		err = e.Topics()
+	case CmdShadows:
+		err = e.Shadows()
+	case CmdJSONStream:
+		err = e.JSONStream()
+	case CmdPreview:
+		err = e.Preview(cmd)
+	case CmdEncrypt:
+		err = e.Encrypt(cmd)
+	case CmdPrintExamples:
+		err = e.PrintExamples(cmd)
+	case CmdRenameAll:
+		err = e.RenameAll(cmd)
+	case CmdNoRender:
+		err = e.printMarkdown(cmd, cmd.HasFlag(StripFrontMatterFlag) || e.cfg.StripFrontMatter)
 	default:
 		err = fmt.Errorf("unrecognized command: '%v' \n", cmd.Cmd)
 	}
 
+	if restoreErr := restoreOutput(); err == nil {
+		err = restoreErr
+	}
 	return err
 }
 
@@ -260,10 +970,16 @@ func (e *Executor) PrintHelp() {
 	fmt.Println()
 	fmt.Printf("\tTo edit cheat-sheet of `git`\n")
 	fmt.Printf("\t$ cs -e git\n")
+	fmt.Println()
+	fmt.Printf("\tTo page through all local cheat-sheets\n")
+	fmt.Printf("\t$ cs --browse\n")
+	fmt.Println()
+	fmt.Printf("\tTo list local cheat-sheets with their descriptions\n")
+	fmt.Printf("\t$ cs -ll\n")
 }
 
 func (e *Executor) PrintVersion() error {
-	tldrVersion, err := e.tldr.Version()
+	tldrVersion, err := e.tldr.Version(e.ctx)
 	if err != nil {
 		return err
 	}
@@ -273,88 +989,1724 @@ func (e *Executor) PrintVersion() error {
 	return nil
 }
 
-func (e *Executor) Find(cmd *Command) error {
-	ok, err := IsFileExists(e.cfg.CheatSheetsDir, cmd.Filename())
-	if err != nil {
-		return err
+// Check is a terse health probe for scripts: it prints `OK`, or
+// `FAIL: reason` and exits non-zero.
+func (e *Executor) Check() error {
+	if err := e.check(); err != nil {
+		fmt.Printf("FAIL: %v\n", err)
+		return &ExitError{Code: 1}
 	}
 
-	if cmd.PrintLog() {
-		log.Printf("has found local cheat-sheet: %v\n", ok)
+	fmt.Println("OK")
+	return nil
+}
+
+func (e *Executor) check() error {
+	if _, err := exec.LookPath(e.cfg.TldrPath); err != nil {
+		return fmt.Errorf("tldr not found: %v", err)
 	}
 
-	if ok {
-		return e.tldr.Render(filepath.Join(e.cfg.CheatSheetsDir, cmd.Filename()))
+	if _, err := e.tldr.Version(e.ctx); err != nil {
+		return fmt.Errorf("tldr --version failed: %v", err)
 	}
 
-	return e.tldr.Find(cmd.Args...)
-}
+	entries, err := os.ReadDir(e.cfg.TldrCachePath)
+	if err != nil || len(entries) == 0 {
+		return fmt.Errorf("tldr cache '%v' is missing or empty", e.cfg.TldrCachePath)
+	}
 
-func (e *Executor) Edit(cmd *Command) error {
-	ok, err := IsFileExists(e.cfg.CheatSheetsDir, cmd.Filename())
+	f, err := os.CreateTemp(e.cfg.CheatSheetsDir, ".check-*")
 	if err != nil {
+		return fmt.Errorf("cheat-sheets directory not writable: %v", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// VersionCheck reports how long ago the tldr cache was last updated, going
+// by its newest file, and warns when it is older than the configured age.
+func (e *Executor) VersionCheck(cmd *Command) error {
+	newest, err := NewestModTime(e.cfg.TldrCachePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		return err
 	}
 
-	if ok {
-		return e.editLocalCheatSheet(cmd)
+	if newest.IsZero() {
+		warnf("tldr cache '%v' is empty, run 'cs -u'", e.cfg.TldrCachePath)
+		return nil
+	}
+
+	now := time.Now()
+	age := now.Sub(newest)
+	fmt.Printf("tldr cache updated %v\n", FormatTime(newest, now, cmd.HasFlag(ISOFlag)))
+	if maxAge := time.Duration(e.cfg.CacheMaxAge) * 24 * time.Hour; maxAge > 0 && age > maxAge {
+		warnf("tldr cache is older than %v, run 'cs -u'", FormatAge(maxAge))
 	}
+	return nil
+}
 
-	dirname, err := e.tldr.FindFileInCache(cmd.Filename())
+func (e *Executor) PrintConfig() error {
+	data, err := yaml.Marshal(e.cfg)
 	if err != nil {
 		return err
 	}
 
-	if cmd.PrintLog() {
-		log.Printf("find cheat sheet stored in '%v' of tldr cache\n", dirname)
+	if len(e.cfg.Layers) > 0 {
+		fmt.Printf("# merged from %v\n", strings.Join(append(e.cfg.Layers, e.cfg.Path), ", "))
 	}
+	fmt.Print(string(data))
+	return nil
+}
 
-	if dirname != "" {
-		src := filepath.Join(dirname, cmd.Filename())
-		dest := filepath.Join(e.cfg.CheatSheetsDir, cmd.Filename())
-		if err := CopyFile(src, dest); err != nil {
+func (e *Executor) Find(cmd *Command) error {
+	if len(cmd.Args) == 1 && IsURL(cmd.Args[0]) {
+		path, err := FetchCachedURL(e.ctx, e.cfg, cmd.Args[0], cmd.HasFlag(OfflineFlag))
+		if err != nil {
 			return err
 		}
+		return e.render(path)
 	}
 
-	return e.editLocalCheatSheet(cmd)
-}
-
-func (e *Executor) editLocalCheatSheet(cmd *Command) error {
-	cheatSheetFilePath := filepath.Join(e.cfg.CheatSheetsDir, cmd.Filename())
-	editCmd := exec.Command(e.cfg.EditorPath, cheatSheetFilePath)
-	editCmd.Stdin = os.Stdin
-	editCmd.Stdout = os.Stdout
-	editCmd.Stderr = os.Stderr
+	if cmd.HasFlag(AllFlag) {
+		return e.FindAll(cmd)
+	}
 
-	return editCmd.Run()
-}
+	e.applyTopicDefaults(cmd)
+	source := cmd.Flags[SourceFlag]
+	switch source {
+	case "", SourceLocal, SourceTldr, SourceAll:
+	default:
+		return fmt.Errorf("invalid -%v '%v', expect one of local, tldr, all", SourceFlag, source)
+	}
 
-func (e *Executor) Update(cmd *Command) error {
-	return e.tldr.Update()
-}
+	var path string
+	if source != SourceTldr {
+		var err error
+		if path, err = e.findLocalCheatSheet(cmd); err != nil {
+			return err
+		}
+	}
 
-func IsFileExists(dirname, filename string) (bool, error) {
-	_, err := os.Stat(filepath.Join(dirname, filename))
-	if err == nil {
-		return true, nil
+	if cmd.PrintLog() {
+		log.Printf("has found local cheat-sheet: %v\n", path != "")
 	}
 
-	if os.IsNotExist(err) {
-		return false, nil
+	if path != "" {
+		e.warnShadow(cmd)
+		return e.renderLocal(cmd, path)
 	}
 
-	return false, err
-}
+	if source == SourceLocal {
+		return fmt.Errorf("%w: no local cheat-sheet '%v'", ErrNotFound, cmd.Topic())
+	}
 
-func CopyFile(src, dest string) error {
-	srcFile, err := os.Open(src)
-	if err != nil {
+	if e.plain {
+		// No output besides the cheat-sheet.
+	} else if err := e.bootstrapCache(cmd); err != nil {
 		return err
 	}
-	defer srcFile.Close()
 
-	destFile, err := os.Create(dest)
+	if lang := cmd.Flags[LangFlag]; lang != "" && lang != "en" {
+		return e.findLocalized(cmd, lang)
+	}
+
+	if e.plain {
+		return e.findPlain(cmd)
+	}
+
+	if cmd.HasFlag(NumberedFlag) || cmd.HasFlag(OnlyFlag) {
+		// Go through the cache to number or select examples of the page.
+		path, err := e.findCachedTopic(cmd.Topic())
+		if err != nil {
+			return err
+		}
+
+		if path != "" {
+			if err := e.renderPage(cmd, path, false); err != nil {
+				return err
+			}
+			e.hintCustomize(cmd)
+			return nil
+		}
+	}
+
+	found, err := e.tldr.FindPage(e.ctx, cmd.Args...)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		e.hintCustomize(cmd)
+		return nil
+	}
+
+	if err := e.suggest(cmd); err != nil {
+		return err
+	}
+
+	if e.cfg.FallbackCommand == "" || cmd.HasFlag(OfflineFlag) {
+		return nil
+	}
+	return e.runFallback(cmd)
+}
+
+// applyTopicDefaults sets the flags of the topic defaults of the config that
+// aren't given, then the language of the config if none is.
+func (e *Executor) applyTopicDefaults(cmd *Command) {
+	defaults, _ := e.cfg.TopicDefaultsFor(cmd.Topic())
+	if defaults.Lang == "" {
+		defaults.Lang = e.cfg.Lang
+	}
+
+	if defaults != (TopicDefaults{}) && cmd.PrintLog() {
+		log.Printf("topic defaults %+v\n", defaults)
+	}
+
+	for name, val := range map[string]string{SourceFlag: defaults.Source, LangFlag: defaults.Lang} {
+		if _, given := cmd.Flags[name]; !given && val != "" {
+			cmd.Flags[name] = val
+		}
+	}
+}
+
+// hintCustomize tells, after a tldr page shown for lack of a local
+// cheat-sheet, how to customize it. It's only for people at a terminal.
+func (e *Executor) hintCustomize(cmd *Command) {
+	if !e.cfg.Hints || e.plain || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return
+	}
+	fmt.Printf("(shown from tldr, run 'cs -e %v' to customize)\n", cmd.Topic())
+}
+
+// findPlain renders the page of the tldr cache rather than letting the tldr
+// client look it up, as it may color it, and errors when there is none.
+func (e *Executor) findPlain(cmd *Command) error {
+	path, err := e.findCachedTopic(cmd.Topic())
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+	return e.renderPage(cmd, path, false)
+}
+
+// FallbackData is the data of the fallback command template, e.g.
+// `xdg-open https://cht.sh/{{.Topic}}`.
+type FallbackData struct {
+	Topic string
+}
+
+// runFallback runs the configured fallback command for a topic found
+// nowhere, with the shell.
+func (e *Executor) runFallback(cmd *Command) error {
+	tmpl, err := template.New("fallback").Parse(e.cfg.FallbackCommand)
+	if err != nil {
+		return fmt.Errorf("parse fallback_command template: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, FallbackData{Topic: cmd.Topic()}); err != nil {
+		return fmt.Errorf("execute fallback_command template: %w", err)
+	}
+
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+
+	if cmd.PrintLog() {
+		log.Printf("run fallback command '%v'\n", b.String())
+	}
+
+	fallbackCmd := exec.CommandContext(e.ctx, shell, "-c", b.String())
+	fallbackCmd.Stdin = os.Stdin
+	fallbackCmd.Stdout = os.Stdout
+	fallbackCmd.Stderr = os.Stderr
+	return fallbackCmd.Run()
+}
+
+// FindMulti renders the cheat-sheet of every argument in turn, rather than
+// taking the arguments as a single multi-word topic. Topics not found are
+// reported at the end.
+func (e *Executor) FindMulti(cmd *Command) error {
+	delim, err := e.delimiter(cmd)
+	if err != nil {
+		return err
+	}
+
+	topics := cmd.Args
+	if topicsFile := cmd.Flags[TopicsFileFlag]; topicsFile != "" {
+		fileTopics, err := ReadTopicsFile(topicsFile)
+		if err != nil {
+			return err
+		}
+		topics = append(fileTopics, topics...)
+	}
+
+	var result BulkResult
+	for _, topic := range topics {
+		result.Add(topic, e.findOne(cmd, delim, topic))
+	}
+
+	if len(result.Failed) > 0 {
+		return result.Summary(os.Stderr, "rendered")
+	}
+	return nil
+}
+
+// findOne renders one of the topics of FindMulti, after the delimiter.
+func (e *Executor) findOne(cmd *Command, delim *Delimiter, topic string) error {
+	localPath, err := e.findLocalTopic(topic)
+	if err != nil {
+		return err
+	}
+
+	path := localPath
+	if path == "" {
+		if path, err = e.findCachedTopic(topic); err != nil {
+			return err
+		}
+	}
+
+	if path == "" {
+		return ErrNotFound
+	}
+
+	source := SourceLocal
+	if localPath == "" {
+		source = filepath.Base(filepath.Dir(path))
+	}
+
+	if err := delim.Next(os.Stdout, HeaderData{Topic: topic, Path: path, Source: source}); err != nil {
+		return err
+	}
+
+	if localPath != "" {
+		return e.renderLocal(cmd, path)
+	}
+	return e.render(path)
+}
+
+// allVariantsHeader is the header of FindAll unless one is configured.
+const allVariantsHeader = "== {{.Topic}} ({{.Source}}) =="
+
+// FindAll renders every variant of the topic in turn, the local cheat-sheet
+// then the page of each page set of the tldr cache, each under a header
+// naming its source.
+func (e *Executor) FindAll(cmd *Command) error {
+	delim, err := e.delimiter(cmd)
+	if err != nil {
+		return err
+	}
+
+	if delim.header.Root == nil || len(delim.header.Root.Nodes) == 0 {
+		if delim, err = NewDelimiter(delim.separator, allVariantsHeader); err != nil {
+			return err
+		}
+	}
+
+	var variants []CheatSheet
+	local, err := e.findLocalCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if local != "" {
+		variants = append(variants, CheatSheet{Topic: cmd.Topic(), Path: local, Local: true})
+	}
+
+	pages, err := e.cachePages()
+	if err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		dir := filepath.Join(e.cfg.TldrCachePath, page)
+		name, err := FindFileFold(dir, cmd.Filename(DefaultExtension))
+		if err != nil {
+			return err
+		}
+
+		if name != "" {
+			variants = append(variants, CheatSheet{Topic: cmd.Topic(), Path: filepath.Join(dir, name)})
+		}
+	}
+
+	if len(variants) == 0 {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+
+	for _, variant := range variants {
+		source := SourceLocal
+		if !variant.Local {
+			source = filepath.Base(filepath.Dir(variant.Path))
+		}
+
+		if err := delim.Next(os.Stdout, HeaderData{Topic: variant.Topic, Path: variant.Path, Source: source}); err != nil {
+			return err
+		}
+
+		if variant.Local {
+			err = e.renderLocal(cmd, variant.Path)
+		} else {
+			err = e.render(variant.Path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cachePages returns the page sets of the tldr cache, the configured ones
+// first in their order, then the others by name.
+func (e *Executor) cachePages() ([]string, error) {
+	entries, err := os.ReadDir(e.cfg.TldrCachePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	exists := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			exists[entry.Name()] = true
+		}
+	}
+
+	var pages []string
+	for _, page := range e.cfg.TldrPages {
+		if exists[page] {
+			pages = append(pages, page)
+			delete(exists, page)
+		}
+	}
+
+	var others []string
+	for page := range exists {
+		others = append(others, page)
+	}
+	sort.Strings(others)
+	return append(pages, others...), nil
+}
+
+// recordUsage logs the access of the topic of the command. Usage tracking is
+// auxiliary, so failures are only logged.
+func (e *Executor) recordUsage(cmd *Command) {
+	if len(cmd.Args) == 0 || IsURL(cmd.Args[0]) {
+		return
+	}
+
+	if err := RecordUsage(e.cfg, cmd.Topic(), time.Now()); err != nil && cmd.PrintLog() {
+		log.Printf("record usage failed: %v\n", err)
+	}
+}
+
+func (e *Executor) Usage(cmd *Command) error {
+	if cmd.HasFlag(ResetFlag) {
+		existing, _ := IsFileExists(e.cfg.DataDir, usageFilename)
+		if !e.confirm(existing, "clear the usage log?") {
+			fmt.Println("kept the usage log")
+			return nil
+		}
+		return ResetUsage(e.cfg)
+	}
+
+	usages, err := LoadUsage(e.cfg)
+	if err != nil {
+		return err
+	}
+
+	if cmd.HasFlag(ReverseFlag) {
+		Reverse(usages)
+	}
+
+	topicWidth := 0
+	for _, usage := range usages {
+		if n := len([]rune(usage.Topic)); n > topicWidth {
+			topicWidth = n
+		}
+	}
+
+	now := time.Now()
+	for _, usage := range usages {
+		fmt.Printf("%-*s  %5d  %v\n", topicWidth, usage.Topic, usage.Count,
+			FormatTime(usage.LastUsed, now, cmd.HasFlag(ISOFlag)))
+	}
+	return nil
+}
+
+// render renders the cheat-sheet file with the configured renderer.
+func (e *Executor) render(path string) error {
+	switch e.cfg.Renderer {
+	case RendererNative:
+		return NewRenderer(e.theme(), os.Stdout).RenderFile(path)
+	case RendererTldr, "":
+		return e.tldr.Render(e.ctx, path)
+	case RendererBat:
+		return e.renderBat(path)
+	default:
+		return fmt.Errorf("unrecognized renderer: '%v'", e.cfg.Renderer)
+	}
+}
+
+// renderContent renders the cheat-sheet content with the configured
+// renderer, going through a temporary file for tldr.
+func (e *Executor) renderContent(content string) error {
+	if e.cfg.Renderer == RendererNative {
+		return NewRenderer(e.theme(), os.Stdout).Render(content)
+	}
+
+	tmp, err := os.CreateTemp("", "cheat-sheet-*."+DefaultExtension)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return e.render(tmp.Name())
+}
+
+// renderLocal renders a local cheat-sheet with the configured substitutions
+// applied to its content.
+func (e *Executor) renderLocal(cmd *Command, path string) error {
+	return e.renderPage(cmd, path, len(e.cfg.Substitutions) > 0 && !cmd.HasFlag(NoSubstFlag))
+}
+
+// renderPage renders the cheat-sheet, substituting tokens if asked, with
+// the examples numbered or selected by the command.
+func (e *Executor) renderPage(cmd *Command, path string, substitute bool) error {
+	numbered := cmd.HasFlag(NumberedFlag)
+	only, err := cmd.IntFlag(OnlyFlag, 0)
+	if err != nil {
+		return err
+	}
+
+	if cmd.HasFlag(OnlyFlag) && only < 1 {
+		return fmt.Errorf("invalid -%v '%v': examples are numbered from 1", OnlyFlag, only)
+	}
+
+	notes := !cmd.HasFlag(ShowNotesFlag)
+	if !substitute && !numbered && only == 0 && !IsEncrypted(path) {
+		if notes {
+			if notes, err = hasNotes(path); err != nil {
+				return err
+			}
+		}
+
+		if !notes {
+			return e.render(path)
+		}
+	}
+
+	data, err := e.readSheet(path)
+	if err != nil {
+		return err
+	}
+
+	content := string(data)
+	if notes {
+		content = StripNotes(content)
+	}
+	if substitute {
+		content = Substitute(content, e.cfg.Substitutions)
+	}
+
+	if numbered || only != 0 {
+		if content, err = SelectExamples(content, numbered, only); err != nil {
+			return err
+		}
+	}
+
+	// Other renderers read a file, which would store the plaintext.
+	if IsEncrypted(path) {
+		return NewRenderer(e.theme(), os.Stdout).Render(content)
+	}
+	return e.renderContent(content)
+}
+
+// hasNotes reports whether the cheat-sheet file has notes, see StripNotes.
+func hasNotes(path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	content := string(data)
+	return StripNotes(content) != content, nil
+}
+
+// findLocalized renders the cached page of the given language, falling back
+// to english unless strict language is requested.
+func (e *Executor) findLocalized(cmd *Command, lang string) error {
+	filename := cmd.Filename(DefaultExtension)
+	path, err := e.tldr.FindLocalizedFileInCache(lang, filename)
+	if err != nil {
+		return err
+	}
+
+	if path != "" {
+		return e.render(path)
+	}
+
+	if cmd.HasFlag(StrictLangFlag) {
+		return fmt.Errorf("%w: '%v' in language '%v'", ErrNotFound, cmd.Topic(), lang)
+	}
+
+	if cmd.PrintLog() {
+		log.Printf("no '%v' page of '%v' in tldr cache, fall back to english\n", lang, cmd.Topic())
+	}
+
+	if e.plain {
+		return e.findPlain(cmd)
+	}
+
+	return e.tldr.Find(e.ctx, cmd.Args...)
+}
+
+func (e *Executor) Edit(cmd *Command) error {
+	if err := requireTopic(cmd, "cs -e <name>"); err != nil {
+		return err
+	}
+
+	path, err := e.findLocalCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if from := cmd.Flags[FromFlag]; from != "" {
+		return e.editFrom(cmd, path, from)
+	}
+
+	if path != "" && IsEncrypted(path) {
+		return e.editEncrypted(cmd, path)
+	}
+
+	if path != "" {
+		return e.editLocalCheatSheet(cmd, path)
+	}
+
+	src, err := e.findAdoptedPage(cmd)
+	if err != nil {
+		return err
+	}
+
+	dest := filepath.Join(e.cfg.CheatSheetsDir, cmd.Filename(e.cfg.Extension))
+	if src != "" {
+		if err := e.adoptFile(cmd, src, dest); err != nil {
+			return err
+		}
+		return e.editLocalCheatSheet(cmd, dest)
+	}
+
+	content, err := e.newSheetContent(cmd)
+	if err != nil {
+		return err
+	}
+
+	if content == nil {
+		return e.editLocalCheatSheet(cmd, dest)
+	}
+	return e.editNewSheet(cmd, dest, content)
+}
+
+// Adopt copies the tldr cache page of the topic into the cheat-sheets
+// directory, like Edit does, without opening the editor. An existing
+// cheat-sheet is only overwritten when forced or confirmed.
+func (e *Executor) Adopt(cmd *Command) error {
+	if err := requireTopic(cmd, "cs --adopt <name>"); err != nil {
+		return err
+	}
+
+	path, err := e.findLocalCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := e.confirmOverwrite(cmd, path); err != nil {
+		return err
+	}
+
+	src, err := e.findAdoptedPage(cmd)
+	if err != nil {
+		return err
+	}
+
+	if src == "" {
+		return fmt.Errorf("%w: '%v' in tldr cache", ErrNotFound, cmd.Topic())
+	}
+
+	if path == "" {
+		path = filepath.Join(e.cfg.CheatSheetsDir, cmd.Filename(e.cfg.Extension))
+	}
+
+	if err := e.adoptFile(cmd, src, path); err != nil {
+		return err
+	}
+
+	fmt.Printf("adopted '%v' from the %v pages\n", cmd.Topic(), filepath.Base(filepath.Dir(src)))
+	return nil
+}
+
+// adoptFile copies the tldr cache page to the cheat-sheets directory. Unless
+// --preserve-mtime=false, the copy keeps the modification time of the page
+// so it doesn't look freshly edited until it is.
+func (e *Executor) adoptFile(cmd *Command, src, dest string) error {
+	if err := CopyFile(src, dest, e.cfg.FilePerm()); err != nil {
+		return err
+	}
+
+	if !cmd.HasFlag(PreserveMtimeFlag) {
+		return nil
+	}
+	return PreserveMtime(src, dest)
+}
+
+// ViewInEditor opens the local cheat-sheet, or else the tldr cache page,
+// read-only in the editor. Nothing is copied into the cheat-sheets directory.
+func (e *Executor) ViewInEditor(cmd *Command) error {
+	path, err := e.resolveCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+
+	editor := e.cfg.EditorFor(path)
+	if cmd.PrintLog() {
+		log.Printf("view '%v' read-only in '%v'\n", path, editor)
+	}
+
+	return e.runEditor(editor, ReadOnlyArgs(editor, path)...)
+}
+
+// Peek prints only the title and description lines of the cheat-sheet.
+func (e *Executor) Peek(cmd *Command) error {
+	path, err := e.resolveCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+
+	data, err := e.readSheet(path)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, ">") {
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+// SectionList prints the headings of the cheat-sheet, indented by level,
+// e.g. to pick one for `--goto`. A tldr page only has its title.
+func (e *Executor) SectionList(cmd *Command) error {
+	path, err := e.resolveCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+
+	data, err := e.readSheet(path)
+	if err != nil {
+		return err
+	}
+
+	for _, heading := range ParseHeadings(StripFrontMatter(string(data))) {
+		fmt.Printf("%v%v %v\n", strings.Repeat("  ", heading.Level-1), strings.Repeat("#", heading.Level), heading.Text)
+	}
+	return nil
+}
+
+// ResolveOnly prints the absolute path of the cheat-sheet, local or cached,
+// and nothing else so that it can be substituted into another command.
+// Failures are reported on stderr. To strip its front-matter, the path of a
+// stripped copy in the data directory is printed instead.
+func (e *Executor) ResolveOnly(cmd *Command) error {
+	path, err := e.resolveCheatSheet(cmd)
+	if err == nil && path == "" {
+		err = fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+	if err == nil && cmd.HasFlag(StripFrontMatterFlag) {
+		path, err = e.strippedCopy(path)
+	}
+	if err == nil {
+		path, err = filepath.Abs(path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return &ExitError{Code: 1}
+	}
+
+	fmt.Println(path)
+	return nil
+}
+
+// strippedCopy returns the path of a copy of the cheat-sheet without its
+// front-matter, or its own path when it has none.
+func (e *Executor) strippedCopy(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	body := StripFrontMatter(string(data))
+	if body == string(data) {
+		return path, nil
+	}
+
+	dirname := filepath.Join(e.cfg.DataDir, "stripped")
+	if err := os.MkdirAll(dirname, e.cfg.DirPerm()); err != nil {
+		return "", err
+	}
+
+	stripped := filepath.Join(dirname, filepath.Base(path))
+	return stripped, WriteFileAtomic(stripped, []byte(body), e.cfg.FilePerm())
+}
+
+// printMarkdown prints the markdown of the cheat-sheet, local or cached, as
+// is unless its front-matter is stripped. For `--raw` it is stripped only
+// when asked, while `--no-render` also follows the config.
+func (e *Executor) printMarkdown(cmd *Command, stripFrontMatter bool) error {
+	path, err := e.resolveCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+
+	data, err := e.readSheet(path)
+	if err != nil {
+		return err
+	}
+
+	if stripFrontMatter {
+		data = []byte(StripFrontMatter(string(data)))
+	}
+
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// ExampleFormats are the presets of `--example-format`, which otherwise
+// takes a template of its own.
+var ExampleFormats = map[string]string{
+	"command":   "{{.Command}}",
+	"commented": "# {{.Description}}\n{{.Command}}",
+	"numbered":  "{{.Index}}. {{.Command}}",
+}
+
+// ExampleData is the data of the example format template, e.g.
+// `{{.Index}}: {{.Command}}`. Index starts at 1.
+type ExampleData struct {
+	Index       int
+	Description string
+	Command     string
+}
+
+// exampleFormat parses the example format of the command, a preset name or
+// a template, the command alone by default.
+func exampleFormat(cmd *Command) (*template.Template, error) {
+	format := cmd.Flags[ExampleFormatFlag]
+	if format == "" {
+		format = "command"
+	}
+
+	if preset, ok := ExampleFormats[format]; ok {
+		format = preset
+	}
+
+	tmpl, err := template.New("example").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("parse example format: %w", err)
+	}
+	return tmpl, nil
+}
+
+func formatExample(tmpl *template.Template, i int, example Example) (string, error) {
+	var b strings.Builder
+	err := tmpl.Execute(&b, ExampleData{Index: i + 1, Description: example.Description, Command: example.Command})
+	return b.String(), err
+}
+
+// PrintExamples prints only the commands of the examples of the cheat-sheet,
+// one per line with their placeholders, e.g. to pipe them into fzf, or else
+// each example in the example format.
+func (e *Executor) PrintExamples(cmd *Command) error {
+	tmpl, err := exampleFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	path, err := e.resolveCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+
+	data, err := e.readSheet(path)
+	if err != nil {
+		return err
+	}
+
+	for i, example := range ParseSheet(string(data)).Examples {
+		if example.Command == "" {
+			continue
+		}
+
+		text, err := formatExample(tmpl, i, example)
+		if err != nil {
+			return err
+		}
+		fmt.Println(text)
+	}
+	return nil
+}
+
+// Clipboard lists the examples of the cheat-sheet and copies the command of
+// the chosen one to the system clipboard.
+func (e *Executor) Clipboard(cmd *Command) error {
+	// Fail before prompting when there is nowhere to copy to.
+	if _, err := ClipboardCommand(); err != nil {
+		return err
+	}
+
+	tmpl, err := exampleFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	path, err := e.resolveCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+
+	data, err := e.readSheet(path)
+	if err != nil {
+		return err
+	}
+
+	examples := ParseSheet(string(data)).Examples
+	if len(examples) == 0 {
+		return fmt.Errorf("cheat-sheet '%v' has no examples", cmd.Topic())
+	}
+
+	for i, example := range examples {
+		fmt.Printf("[%d] %v\n    %v\n", i+1, example.Description, example.Command)
+	}
+
+	fmt.Printf("copy example [1-%d]: ", len(examples))
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(examples) {
+		return fmt.Errorf("invalid example '%v', expect a number from 1 to %d", strings.TrimSpace(line), len(examples))
+	}
+
+	text, err := formatExample(tmpl, n-1, examples[n-1])
+	if err != nil {
+		return err
+	}
+
+	if err := CopyToClipboard(text); err != nil {
+		return err
+	}
+
+	fmt.Printf("copied: %v\n", text)
+	return nil
+}
+
+// resolveCheatSheet returns the path of the local cheat-sheet of the command,
+// or else of its page in the tldr cache, or an empty string if neither exists.
+func (e *Executor) resolveCheatSheet(cmd *Command) (string, error) {
+	path, err := e.findLocalCheatSheet(cmd)
+	if err != nil || path != "" {
+		return path, err
+	}
+
+	return e.findCachedTopic(cmd.Topic())
+}
+
+// findCachedTopic returns the path of the page of the topic in the tldr
+// cache, or an empty string if there isn't one.
+func (e *Executor) findCachedTopic(topic string) (string, error) {
+	topic = NormalizeTopic(topic)
+	return e.tldr.FindFileInCache(topic + "." + DefaultExtension)
+}
+
+// PlatformPage returns the tldr page set of the current OS, e.g. `osx` on
+// macOS.
+func PlatformPage() string {
+	if runtime.GOOS == "darwin" {
+		return "osx"
+	}
+	return runtime.GOOS
+}
+
+// findAdoptedPage returns the page of the tldr cache that Edit and Adopt
+// copy: the one of the `--page` page set if given, else the one of the
+// current platform, else the first of the configured page sets.
+func (e *Executor) findAdoptedPage(cmd *Command) (string, error) {
+	pages := append([]string{PlatformPage()}, e.cfg.TldrPages...)
+	if page := cmd.Flags[PageFlag]; page != "" {
+		pages = []string{page}
+	}
+
+	filename := cmd.Filename(DefaultExtension)
+	for _, page := range pages {
+		dir := filepath.Join(e.cfg.TldrCachePath, page)
+		name, err := FindFileFold(dir, filename)
+		if err != nil {
+			return "", err
+		}
+
+		if name == "" {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		if err := CheckReadable(path); err != nil {
+			if cmd.PrintLog() {
+				log.Printf("skip cache page: %v\n", err)
+			}
+			continue
+		}
+
+		if cmd.PrintLog() {
+			log.Printf("adopt '%v' of the %v pages\n", path, page)
+		}
+		return path, nil
+	}
+
+	if cmd.PrintLog() {
+		log.Printf("no page of '%v' in %v of the tldr cache\n", cmd.Topic(), strings.Join(pages, ", "))
+	}
+	return "", nil
+}
+
+// editFrom seeds the cheat-sheet of the command with a copy of the one of
+// the from topic, retitled, then edits it. An existing cheat-sheet at path
+// is only overwritten when forced or confirmed.
+func (e *Executor) editFrom(cmd *Command, path, from string) error {
+	if err := e.confirmOverwrite(cmd, path); err != nil {
+		return err
+	}
+
+	src, err := e.findLocalTopic(from)
+	if err != nil {
+		return err
+	}
+
+	if src == "" {
+		if src, err = e.findCachedTopic(from); err != nil {
+			return err
+		}
+	}
+
+	if src == "" {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, from)
+	}
+
+	data, err := e.readSheet(src)
+	if err != nil {
+		return err
+	}
+
+	if cmd.PrintLog() {
+		log.Printf("seed cheat sheet from '%v'\n", src)
+	}
+
+	if path == "" {
+		path = filepath.Join(e.cfg.CheatSheetsDir, cmd.Filename(e.cfg.Extension))
+	}
+
+	if err := e.checkSizeCap(cmd, cmd.Topic(), int64(len(data))); err != nil {
+		return err
+	}
+
+	if err := e.checkText(cmd, cmd.Topic(), data); err != nil {
+		return err
+	}
+
+	content := Retitle(string(data), strings.Join(cmd.Args, " "))
+	if err := e.saveSheet(path, []byte(content)); err != nil {
+		return err
+	}
+
+	if IsEncrypted(path) {
+		return e.editEncrypted(cmd, path)
+	}
+	return e.editLocalCheatSheet(cmd, path)
+}
+
+// findLocalCheatSheet returns the path of the local cheat-sheet of the
+// command, or an empty string if there isn't one.
+func (e *Executor) findLocalCheatSheet(cmd *Command) (string, error) {
+	return e.findLocalTopic(cmd.Topic())
+}
+
+func (e *Executor) findLocalTopic(topic string) (string, error) {
+	topic = NormalizeTopic(topic)
+	for _, ext := range e.cfg.Extensions() {
+		for _, filename := range []string{topic + "." + ext, topic + "." + ext + EncryptedExt} {
+			name, err := FindFileFold(e.cfg.CheatSheetsDir, filename)
+			if err != nil {
+				return "", err
+			}
+
+			if name != "" {
+				return filepath.Join(e.cfg.CheatSheetsDir, name), nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+func (e *Executor) editLocalCheatSheet(cmd *Command, path string) error {
+	editor := e.cfg.EditorFor(path)
+	args := []string{path}
+	if section := cmd.Flags[GotoFlag]; section != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		line := FindSection(string(data), section)
+		if cmd.PrintLog() {
+			log.Printf("found section '%v' at line %d\n", section, line)
+		}
+		args = GotoArgs(editor, path, line)
+	}
+
+	before, err := os.ReadFile(path)
+	existed := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if cmd.PrintLog() {
+		log.Printf("edit '%v' in '%v'\n", path, editor)
+	}
+
+	if err := e.editFile(cmd, editor, args...); err != nil {
+		return err
+	}
+
+	if err := e.refuseBinary(cmd, path, before, existed); err != nil {
+		return err
+	}
+
+	// Too late to refuse, but point out what was likely pasted by mistake.
+	if info, err := os.Stat(path); err == nil && e.cfg.Oversized(info.Size()) {
+		warnf("cheat-sheet '%v' is %v, over the size cap of %v", cmd.Topic(),
+			FormatSize(info.Size()), FormatSize(e.cfg.SizeCap))
+	}
+	return nil
+}
+
+// refuseBinary restores the cheat-sheet at path to its content before
+// editing, or removes it if it didn't exist, when it was saved as binary data
+// unless forced.
+func (e *Executor) refuseBinary(cmd *Command, path string, before []byte, existed bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := e.checkText(cmd, cmd.Topic(), data); err == nil {
+		return nil
+	}
+
+	if existed {
+		err = WriteFileAtomic(path, before, e.cfg.FilePerm())
+	} else {
+		err = os.Remove(path)
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("cheat-sheet '%v' was saved as binary data, not text, so the edit was undone, use --force to keep it", cmd.Topic())
+}
+
+// checkSizeCap refuses to write a cheat-sheet of the topic over the size
+// cap unless forced.
+func (e *Executor) checkSizeCap(cmd *Command, topic string, size int64) error {
+	if !e.cfg.Oversized(size) || cmd.HasFlag(ForceFlag) {
+		return nil
+	}
+
+	return fmt.Errorf("cheat-sheet '%v' would be %v, over the size cap of %v, use --force to write it anyway",
+		topic, FormatSize(size), FormatSize(e.cfg.SizeCap))
+}
+
+// checkText refuses to write a cheat-sheet of the topic that isn't text,
+// which can't render, unless forced.
+func (e *Executor) checkText(cmd *Command, topic string, data []byte) error {
+	if !IsBinary(data) || cmd.HasFlag(ForceFlag) {
+		return nil
+	}
+
+	return fmt.Errorf("cheat-sheet '%v' would be binary data, not text, use --force to write it anyway", topic)
+}
+
+// warnOversized warns about cheat-sheets over the size cap.
+func (e *Executor) warnOversized(sheets []CheatSheet) {
+	for _, sheet := range sheets {
+		if info, err := os.Stat(sheet.Path); err == nil && e.cfg.Oversized(info.Size()) {
+			warnf("cheat-sheet '%v' is %v, over the size cap of %v", sheet.Topic,
+				FormatSize(info.Size()), FormatSize(e.cfg.SizeCap))
+		}
+	}
+}
+
+// editFile runs the editor on a file that is used afterwards. An editor
+// exiting non-zero, e.g. `:cq` in vim, may still have saved the file, so it
+// only warns unless --strict-editor. Failing to start the editor is fatal.
+func (e *Executor) editFile(cmd *Command, editor string, args ...string) error {
+	err := e.runEditor(editor, args...)
+	var exitErr *exec.ExitError
+	if err == nil || cmd.HasFlag(StrictEditorFlag) || e.ctx.Err() != nil || !errors.As(err, &exitErr) {
+		return err
+	}
+
+	warnf("editor '%v' exited with code %d, keep going, use --strict-editor to stop instead", editor, exitErr.ExitCode())
+	return nil
+}
+
+func (e *Executor) runEditor(editor string, args ...string) error {
+	editCmd := exec.CommandContext(e.ctx, editor, args...)
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+
+	return editCmd.Run()
+}
+
+// Browse renders local cheat-sheets one by one, prompting between them
+// like flashcards.
+func (e *Executor) Browse(cmd *Command) error {
+	sheets, missing, err := e.selectedSheets(cmd)
+	if err != nil {
+		return err
+	}
+
+	err = e.browse(cmd, sheets)
+	if len(missing) > 0 {
+		warnf("%v: %v", ErrNotFound, strings.Join(missing, ", "))
+	}
+	return err
+}
+
+func (e *Executor) browse(cmd *Command, sheets []CheatSheet) error {
+	if len(sheets) == 0 {
+		fmt.Println("no cheat-sheets found")
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for i := 0; i < len(sheets); {
+		render := e.render
+		if sheets[i].Local {
+			render = func(path string) error { return e.renderLocal(cmd, path) }
+		}
+
+		if err := render(sheets[i].Path); err != nil {
+			return err
+		}
+
+		fmt.Printf("[%d/%d] (n)ext, (p)rev, (q)uit: ", i+1, len(sheets))
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				fmt.Println()
+				return nil
+			}
+			return err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "q", "quit":
+			return nil
+		case "p", "prev":
+			if i > 0 {
+				i--
+			}
+		default:
+			i++
+		}
+	}
+
+	return nil
+}
+
+// overrideMark marks the local cheat-sheets overriding a tldr page in the
+// list of all of them.
+const overrideMark = " (local override)"
+
+// List lists the cheat-sheets of the --source: local ones by default, the
+// pages of the tldr cache, or all of them once each, marking the local ones
+// overriding a page.
+func (e *Executor) List(cmd *Command) error {
+	source := cmd.Flags[SourceFlag]
+	var sheets []CheatSheet
+	overrides := make(map[string]bool)
+	switch source {
+	case "", SourceLocal, SourceAll:
+		local, err := LocalCheatSheets(e.cfg)
+		if err != nil {
+			return err
+		}
+
+		e.warnOversized(local)
+		sheets = local
+		if source != SourceAll {
+			break
+		}
+
+		cached, err := CachedCheatSheets(e.cfg.TldrCachePath, e.cfg.TldrPages)
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[string]bool)
+		for _, sheet := range local {
+			seen[sheet.Topic] = true
+		}
+
+		for _, sheet := range cached {
+			if seen[sheet.Topic] {
+				overrides[sheet.Topic] = true
+			} else {
+				sheets = append(sheets, sheet)
+			}
+		}
+
+		sort.Slice(sheets, func(i, j int) bool {
+			return sheets[i].Topic < sheets[j].Topic
+		})
+	case SourceTldr:
+		cached, err := CachedCheatSheets(e.cfg.TldrCachePath, e.cfg.TldrPages)
+		if err != nil {
+			return err
+		}
+		sheets = cached
+	default:
+		return fmt.Errorf("invalid -%v '%v', expect one of local, tldr, all", SourceFlag, source)
+	}
+
+	if cmd.HasFlag(ReverseFlag) {
+		Reverse(sheets)
+	}
+
+	labels := make([]string, len(sheets))
+	for i, sheet := range sheets {
+		labels[i] = sheet.Topic
+		if overrides[sheet.Topic] {
+			labels[i] += overrideMark
+		}
+	}
+
+	if !cmd.HasFlag(LongFlag) {
+		for _, label := range labels {
+			fmt.Println(label)
+		}
+		return nil
+	}
+
+	labelWidth := 0
+	for _, label := range labels {
+		if n := len([]rune(label)); n > labelWidth {
+			labelWidth = n
+		}
+	}
+
+	descWidth := TerminalWidth() - labelWidth - 2
+	for i, sheet := range sheets {
+		desc, err := ParseDescription(sheet.Path)
+		if err != nil {
+			return err
+		}
+
+		line := fmt.Sprintf("%-*s  %s", labelWidth, labels[i], Truncate(desc, descWidth))
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	return nil
+}
+
+func (e *Executor) Stats(cmd *Command) error {
+	stats, err := CollectStats(e.cfg, e.tldr)
+	if err != nil {
+		return err
+	}
+
+	sheets, err := LocalCheatSheets(e.cfg)
+	if err != nil {
+		return err
+	}
+	e.warnOversized(sheets)
+
+	format := cmd.Flags[FormatFlag]
+	if cmd.HasFlag(JSONFlag) {
+		format = "json"
+	}
+
+	switch format {
+	case "", "table":
+		return stats.WriteTable(os.Stdout)
+	case "json":
+		return stats.WriteJSON(os.Stdout)
+	case "prom":
+		return stats.WriteProm(os.Stdout)
+	default:
+		return fmt.Errorf("unrecognized stats format: '%v'", format)
+	}
+}
+
+// Merge appends the examples of the src cheat-sheet to the dest one, keeping
+// the title and description of dest.
+func (e *Executor) Merge(cmd *Command) error {
+	if len(cmd.Args) != 2 {
+		return errors.New("usage: cs --merge <src> <dest>")
+	}
+
+	src, dest := cmd.Args[0], cmd.Args[1]
+	if NormalizeTopic(src) == "" || NormalizeTopic(dest) == "" {
+		return fmt.Errorf("%w, usage: cs --merge <src> <dest>", ErrEmptyTopic)
+	}
+
+	srcPath, err := e.findLocalTopic(src)
+	if err != nil {
+		return err
+	}
+
+	destPath, err := e.findLocalTopic(dest)
+	if err != nil {
+		return err
+	}
+
+	if srcPath == "" || destPath == "" {
+		return fmt.Errorf("%w: '%v' and '%v' must both be local cheat-sheets", ErrNotFound, src, dest)
+	}
+
+	srcData, err := e.readSheet(srcPath)
+	if err != nil {
+		return err
+	}
+
+	destData, err := e.readSheet(destPath)
+	if err != nil {
+		return err
+	}
+
+	destSheet := ParseSheet(string(destData))
+	merged := strings.TrimRight(string(destData), "\n") + "\n"
+	added := 0
+	for _, example := range ParseSheet(string(srcData)).Examples {
+		if cmd.HasFlag(DedupeFlag) && destSheet.HasCommand(example.Command) {
+			continue
+		}
+
+		merged += "\n" + example.String()
+		added++
+	}
+
+	if err := ValidateSheet(merged); err != nil {
+		return fmt.Errorf("merged cheat-sheet is invalid: %w", err)
+	}
+
+	if err := e.checkSizeCap(cmd, dest, int64(len(merged))); err != nil {
+		return err
+	}
+
+	if err := e.checkText(cmd, dest, []byte(merged)); err != nil {
+		return err
+	}
+
+	if err := e.saveSheet(destPath, []byte(merged)); err != nil {
+		return err
+	}
+
+	fmt.Printf("merged %d examples of '%v' into '%v'\n", added, src, dest)
+	if !cmd.HasFlag(DeleteSourceFlag) {
+		return nil
+	}
+
+	if !e.confirm(true, "delete cheat-sheet '%v'?", src) {
+		fmt.Printf("kept '%v'\n", src)
+		return nil
+	}
+	return os.Remove(srcPath)
+}
+
+// confirmOverwrite returns an error unless there is no cheat-sheet at path,
+// or overwriting it is forced or confirmed.
+func (e *Executor) confirmOverwrite(cmd *Command, path string) error {
+	if cmd.HasFlag(ForceFlag) {
+		return nil
+	}
+
+	if path == "" {
+		if e.confirm(false, "create cheat-sheet '%v'?", cmd.Topic()) {
+			return nil
+		}
+		return fmt.Errorf("cheat-sheet '%v' not created", cmd.Topic())
+	}
+
+	if e.confirm(true, "overwrite cheat-sheet '%v' at '%v'?", cmd.Topic(), path) {
+		return nil
+	}
+	return fmt.Errorf("cheat-sheet '%v' already exists at '%v', use --force to overwrite it", cmd.Topic(), path)
+}
+
+// DedupeCache reports identical files across the page directories of the
+// tldr cache, and replaces them by hardlinks if asked to.
+func (e *Executor) DedupeCache(cmd *Command) error {
+	groups, err := FindDuplicates(e.cfg.TldrCachePath)
+	if err != nil {
+		return err
+	}
+
+	var savings int64
+	var result BulkResult
+	for _, group := range groups {
+		fmt.Printf("%d copies of %d bytes:\n", len(group.Paths), group.Size)
+		for _, path := range group.Paths {
+			fmt.Printf("\t%v\n", path)
+		}
+
+		if cmd.HasFlag(HardlinkFlag) {
+			err := group.Hardlink()
+			result.Add(group.Paths[0], err)
+			if err != nil {
+				continue
+			}
+		}
+		savings += group.Savings()
+	}
+
+	if cmd.HasFlag(HardlinkFlag) {
+		fmt.Printf("%d bytes saved\n", savings)
+		return result.Summary(os.Stdout, "hardlinked groups")
+	}
+
+	fmt.Printf("%d duplicate groups, %d bytes could be saved with --hardlink\n", len(groups), savings)
+	return nil
+}
+
+// update updates the tldr cache, holding the update lock so that concurrent
+// invocations don't update at the same time. When another update is running,
+// it waits for it instead.
+func (e *Executor) update() error {
+	return e.lockedUpdate(func() error {
+		return e.tldr.Update(e.ctx)
+	})
+}
+
+// updatePage is update of the pages of a single page set, falling back to
+// updating the whole cache when the client can't.
+func (e *Executor) updatePage(cmd *Command, page string) error {
+	return e.lockedUpdate(func() error {
+		updated, err := e.tldr.UpdatePage(e.ctx, page)
+		if err != nil || updated {
+			return err
+		}
+
+		if cmd.PrintLog() {
+			log.Printf("tldr client can't update only the %v pages, update all of them\n", page)
+		}
+		return e.tldr.Update(e.ctx)
+	})
+}
+
+func (e *Executor) lockedUpdate(update func() error) error {
+	lock := NewFileLock(e.cfg, filepath.Join(e.cfg.DataDir, updateLockFilename))
+	ok, err := lock.TryLock()
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		fmt.Println("waiting for another update of the tldr cache...")
+		return lock.Wait()
+	}
+	defer lock.Unlock()
+
+	return update()
+}
+
+// bootstrapCache populates the tldr cache once when it's missing or empty,
+// e.g. on a new machine.
+func (e *Executor) bootstrapCache(cmd *Command) error {
+	if !e.cfg.AutoBootstrap || cmd.HasFlag(OfflineFlag) {
+		return nil
+	}
+
+	entries, err := os.ReadDir(e.cfg.TldrCachePath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if len(entries) > 0 {
+		return nil
+	}
+
+	fmt.Println("tldr cache is empty, populating it once...")
+	return e.update()
+}
+
+// Update updates the tldr cache. When a page is given, only the pages of that
+// page set are updated if the client can, else the whole cache is, and only
+// the changes of that page are reported either way.
+func (e *Executor) Update(cmd *Command) error {
+	page := cmd.Flags[PageFlag]
+	if page == "" {
+		return e.update()
+	}
+
+	pageDir := filepath.Join(e.cfg.TldrCachePath, page)
+	before, err := SnapshotPage(pageDir)
+	if err != nil {
+		return err
+	}
+
+	if err := e.updatePage(cmd, page); err != nil {
+		return err
+	}
+
+	after, err := SnapshotPage(pageDir)
+	if err != nil {
+		return err
+	}
+
+	changes := before.Diff(after)
+	fmt.Printf("%v: %d added, %d removed, %d updated\n",
+		page, len(changes.Added), len(changes.Removed), len(changes.Updated))
+	for _, name := range changes.Added {
+		fmt.Printf("\t+ %v\n", name)
+	}
+	for _, name := range changes.Removed {
+		fmt.Printf("\t- %v\n", name)
+	}
+	for _, name := range changes.Updated {
+		fmt.Printf("\t~ %v\n", name)
+	}
+
+	return nil
+}
+
+// Reverse reverses the order of the elements in place.
+func Reverse[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+func IsFileExists(dirname, filename string) (bool, error) {
+	_, err := os.Stat(filepath.Join(dirname, filename))
+	if err == nil {
+		return true, nil
+	}
+
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// CheckReadable returns an error if the file is empty or can't be read.
+func CheckReadable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 1)
+	if _, err := f.Read(buf); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("'%v' is empty", path)
+		}
+		return fmt.Errorf("read '%v': %w", path, err)
+	}
+	return nil
+}
+
+// FindFileFold returns the name of the file of the directory matching the
+// filename regardless of case, preferring an exact match, or an empty
+// string if there is none.
+func FindFileFold(dirname, filename string) (string, error) {
+	ok, err := IsFileExists(dirname, filename)
+	if err != nil {
+		return "", err
+	}
+
+	if ok {
+		return filename, nil
+	}
+
+	entries, err := os.ReadDir(dirname)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Name(), filename) {
+			return entry.Name(), nil
+		}
+	}
+
+	return "", nil
+}
+
+func CopyFile(src, dest string, perm os.FileMode) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	destFile, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
 	if err != nil {
 		return err
 	}
@@ -363,3 +2715,58 @@ func CopyFile(src, dest string) error {
 	_, err = io.Copy(destFile, srcFile)
 	return err
 }
+
+// PreserveMtime sets the access and modification times of dest to the ones
+// of src.
+func PreserveMtime(src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(dest, info.ModTime(), info.ModTime())
+}
+
+// WriteFileAtomic writes data to a temporary file next to path, then renames
+// it over path so readers never see a partial file. A symlink at path is
+// followed, so the file it links to is replaced and the link kept.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeFileAtomic(path, data, perm, false)
+}
+
+// writeFileAtomic is WriteFileAtomic, syncing the data to disk before the
+// rename when sync is set so it survives a crash too.
+func writeFileAtomic(path string, data []byte, perm os.FileMode, sync bool) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if sync {
+		if err := tmp.Sync(); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
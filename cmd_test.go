@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func parseCommand(t *testing.T, args ...string) *Command {
+	t.Helper()
+	fs := NewFlagSet()
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("parse %q failed: %v", args, err)
+	}
+	return CreateCommand(fs)
+}
+
+func TestCreateCommandDashTopics(t *testing.T) {
+	tests := []struct {
+		args     []string
+		wantCmd  CmdKind
+		wantArgs []string
+	}{
+		{[]string{"git", "commit"}, CmdFind, []string{"git", "commit"}},
+		{[]string{"--", "--weird-topic"}, CmdFind, []string{"--weird-topic"}},
+		{[]string{"--", "-foo"}, CmdFind, []string{"-foo"}},
+		{[]string{"--", "-"}, CmdFind, []string{"-"}},
+		{[]string{"--", "git", "--amend"}, CmdFind, []string{"git", "--amend"}},
+		{[]string{"--plain", "--", "-foo"}, CmdFind, []string{"-foo"}},
+		{[]string{"-e", "-foo"}, CmdEdit, []string{"-foo"}},
+		{[]string{"--adopt", "--weird-topic"}, CmdAdopt, []string{"--weird-topic"}},
+	}
+
+	for _, tt := range tests {
+		cmd := parseCommand(t, tt.args...)
+		if cmd.Cmd != tt.wantCmd || !reflect.DeepEqual(cmd.Args, tt.wantArgs) {
+			t.Errorf("CreateCommand(%q) = %v %q, want %v %q", tt.args, cmd.Cmd, cmd.Args, tt.wantCmd, tt.wantArgs)
+		}
+	}
+}
+
+func TestTopicArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"git"}, []string{"git"}},
+		{[]string{"git", "commit"}, []string{"git", "commit"}},
+		{[]string{"-foo"}, []string{"--", "-foo"}},
+		{[]string{"--weird-topic"}, []string{"--", "--weird-topic"}},
+		{[]string{"git", "--amend"}, []string{"--", "git", "--amend"}},
+	}
+
+	for _, tt := range tests {
+		if got := topicArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("topicArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestEmptyTopicRefused(t *testing.T) {
+	tests := [][]string{
+		{"-e", ""},
+		{"-e"},
+		{"--e", "  "},
+		{"--adopt", ""},
+		{"--adopt"},
+		{"--encrypt"},
+	}
+
+	for _, args := range tests {
+		cmd := parseCommand(t, completeTopicFlag(args)...)
+		cfg := testConfig(t)
+		err := NewExecutor(cfg, WithTldr(&fakeTldr{})).Exec(context.Background(), cmd)
+		if !errors.Is(err, ErrEmptyTopic) {
+			t.Errorf("cs %q = %v, want %v", args, err, ErrEmptyTopic)
+		}
+	}
+}
+
+func TestCompleteTopicFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{nil, nil},
+		{[]string{"git"}, []string{"git"}},
+		{[]string{"-e"}, []string{"-e", ""}},
+		{[]string{"--merge"}, []string{"--merge", ""}},
+		{[]string{"-e", "git"}, []string{"-e", "git"}},
+		{[]string{"--", "-e"}, []string{"--", "-e"}},
+	}
+
+	for _, tt := range tests {
+		if got := completeTopicFlag(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("completeTopicFlag(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		client string
+		output string
+		want   string
+	}{
+		{"tealdeer", "tealdeer 1.6.1\n", "1.6.1"},
+		{"tealdeer pre-release", "tealdeer 1.7.0-rc.1\n", "1.7.0-rc.1"},
+		{"node", "v3.4.0\n", "3.4.0"},
+		{"python", "tldr 3.2.0 (tldr-client specification 1.5)\n", "3.2.0"},
+		{"tlrc", "tlrc v1.9.3 (implementing the tldr client specification v2.2)\n", "1.9.3"},
+		{"c", "tldr v1.6.1\nCopyright (C) 2016 Arvid Gerstmann\nSource available at https://github.com/tldr-pages/tldr-c-client\n", "1.6.1"},
+		{"two-part version", "tldr 2.0\n", "2.0"},
+		{"no version", "tldr (dev build)\n", "tldr (dev build)"},
+		{"leading blank lines", "\n\n  custom client  \n", "custom client"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := ParseVersion(tt.output); got != tt.want {
+			t.Errorf("ParseVersion of %v %q = %q, want %q", tt.client, tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestCheckReadable(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"ok.md": "# ok\n", "empty.md": ""}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "dir.md"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"ok.md", true},
+		{"empty.md", false},
+		{"dir.md", false},
+		{"missing.md", false},
+	}
+
+	for _, tt := range tests {
+		if err := CheckReadable(filepath.Join(dir, tt.name)); (err == nil) != tt.ok {
+			t.Errorf("CheckReadable(%v) = %v, want ok %v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestCheckReadableUnreadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root reads any file")
+	}
+
+	path := filepath.Join(t.TempDir(), "secret.md")
+	if err := os.WriteFile(path, []byte("# secret\n"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckReadable(path); err == nil {
+		t.Error("CheckReadable of an unreadable file succeeded")
+	}
+}
+
+func TestFindFileInCacheSkipsBrokenPages(t *testing.T) {
+	cache := t.TempDir()
+	for _, page := range []string{"common", "linux", "osx"} {
+		if err := os.Mkdir(filepath.Join(cache, page), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Left empty by an interrupted update, or a directory by mistake.
+	write := func(page, name, content string) {
+		if err := os.WriteFile(filepath.Join(cache, page, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("common", "tar.md", "")
+	write("linux", "tar.md", "# tar\n")
+	write("common", "ls.md", "")
+	if err := os.Mkdir(filepath.Join(cache, "common", "git.md"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write("osx", "git.md", "# git\n")
+
+	tldr := NewTldr("tldr", cache, []string{"common", "linux", "osx"})
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"tar.md", filepath.Join(cache, "linux", "tar.md")},
+		{"git.md", filepath.Join(cache, "osx", "git.md")},
+		{"ls.md", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := tldr.FindFileInCache(tt.filename)
+		if err != nil {
+			t.Fatalf("FindFileInCache(%v) failed: %v", tt.filename, err)
+		}
+		if got != tt.want {
+			t.Errorf("FindFileInCache(%v) = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestFindFileFold(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"Git.md", "git.MD", "Docker.md", "tar.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Case-insensitive file systems match any case, so the exact match can't
+	// be told apart there.
+	caseSensitive := true
+	if _, err := os.Stat(filepath.Join(dir, "TAR.md")); err == nil {
+		caseSensitive = false
+	}
+
+	tests := []struct {
+		filename string
+		want     string
+		exact    bool
+	}{
+		{"Git.md", "Git.md", true},
+		{"git.MD", "git.MD", true},
+		{"docker.md", "Docker.md", false},
+		{"DOCKER.MD", "Docker.md", false},
+		{"TAR.md", "tar.md", false},
+		{"tar.md", "tar.md", true},
+		{"kubectl.md", "", true},
+	}
+
+	for _, tt := range tests {
+		if !tt.exact && !caseSensitive {
+			continue
+		}
+
+		got, err := FindFileFold(dir, tt.filename)
+		if err != nil {
+			t.Fatalf("FindFileFold(%v) failed: %v", tt.filename, err)
+		}
+		if got != tt.want {
+			t.Errorf("FindFileFold(%v) = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+
+	if got, err := FindFileFold(filepath.Join(dir, "missing"), "git.md"); err != nil || got != "" {
+		t.Errorf("FindFileFold of a missing dir = %q, %v, want nothing", got, err)
+	}
+}
+
+func TestNormalizeTopic(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  string
+	}{
+		{[]string{"git"}, "git"},
+		{[]string{"git", "commit"}, "git-commit"},
+		{[]string{"git commit"}, "git-commit"},
+		{[]string{"git-commit"}, "git-commit"},
+		{[]string{"docker", "run"}, "docker-run"},
+		{[]string{"Docker", "RUN"}, "docker-run"},
+		{[]string{"  docker  ", " run "}, "docker-run"},
+		{[]string{"docker\trun"}, "docker-run"},
+		{[]string{"docker", "compose", "up"}, "docker-compose-up"},
+		{[]string{""}, ""},
+		{nil, ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeTopic(tt.words...); got != tt.want {
+			t.Errorf("NormalizeTopic(%q) = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,551 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidConfig = errors.New("invalid config file")
+
+const (
+	ConfigFilename     = "config.yaml"
+	TOMLConfigFilename = "config.toml"
+	DefaultExtension   = "md"
+	DefaultSizeCap     = 1 << 20
+	DefaultCacheMaxAge = 30
+	DefaultDirMode     = "0755"
+	DefaultFileMode    = "0644"
+	// ProfileEnv names the profile used when none is given with --profile.
+	ProfileEnv = "CHEAT_SHEET_PROFILE"
+	// DirEnv overrides the cheat-sheets directory when not given with --dir.
+	DirEnv = "CHEAT_SHEET_DIR"
+	// CacheDirEnv overrides the tldr cache directory when not given with
+	// --cache-dir.
+	CacheDirEnv = "TLDR_CACHE"
+)
+
+// DefaultCheatSheetsDir returns the default directory of cheat-sheets, which
+// also holds the config files: $CHEAT_SHEET_DIR or else ~/.cheat-sheet.
+func DefaultCheatSheetsDir() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+
+	dirname, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dirname, ".cheat-sheet"), nil
+}
+
+// readOnlyHint explains permission errors of the cheat-sheets directory or
+// config file, typical of read-only home directories, e.g. in containers.
+func readOnlyHint(err error) error {
+	if errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EROFS) {
+		return fmt.Errorf("%w; the filesystem may be read-only, use --dir or %v to point to a writable directory", err, DirEnv)
+	}
+	return err
+}
+
+// configFilenames returns the yaml and toml config filenames of the profile,
+// e.g. `config.work.yaml`, or the default ones without a profile.
+func configFilenames(profile string) []string {
+	if profile == "" {
+		return []string{ConfigFilename, TOMLConfigFilename}
+	}
+	return []string{"config." + profile + ".yaml", "config." + profile + ".toml"}
+}
+
+// Profiles lists the profiles having a config file in the directory.
+func Profiles(dirname string) ([]string, error) {
+	entries, err := os.ReadDir(dirname)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	var profiles []string
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if entry.IsDir() || !strings.HasPrefix(name, "config.") || (ext != ".yaml" && ext != ".toml") {
+			continue
+		}
+
+		profile := strings.TrimSuffix(strings.TrimPrefix(name, "config."), ext)
+		if profile != "" && !strings.Contains(profile, ".") && !found[profile] {
+			found[profile] = true
+			profiles = append(profiles, profile)
+		}
+	}
+
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// DefaultConfig returns the default config overridden by the config file.
+// Unless config file paths are given, `config.yaml` of the cheat-sheets
+// directory is used, or else `config.toml`. Several paths are layered in
+// order, later files overriding keys of earlier ones, lists included, and
+// merging into their maps. A profile uses its own
+// `config.<profile>.yaml` or `.toml` instead, and its own data directory.
+// The cheat-sheets directory is dir if given, else DefaultCheatSheetsDir.
+func DefaultConfig(configPaths []string, profile, dir string) (*Config, error) {
+	dirname, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ContainsAny(profile, `/\.`) {
+		return nil, fmt.Errorf("invalid profile name '%v'", profile)
+	}
+
+	cheatSheetDir := dir
+	if cheatSheetDir == "" {
+		if cheatSheetDir, err = DefaultCheatSheetsDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	dataDir := filepath.Join(dirname, ".local/share/cheat-sheet")
+	if xdgDataHome := os.Getenv("XDG_DATA_HOME"); xdgDataHome != "" {
+		dataDir = filepath.Join(xdgDataHome, "cheat-sheet")
+	}
+	if profile != "" {
+		dataDir = filepath.Join(dataDir, "profiles", profile)
+	}
+
+	tldrCachePath := filepath.Join(dirname, ".tldr/cache/pages")
+	cfg := &Config{
+		CheatSheetsDir:   cheatSheetDir,
+		DataDir:          dataDir,
+		TldrPath:         "tldr",
+		TldrCachePath:    tldrCachePath,
+		TldrPages:        []string{"common", "linux"},
+		TldrNotFoundCode: DefaultTldrNotFoundCode,
+		EditorPath:       "vim",
+		Extension:        DefaultExtension,
+		Renderer:         RendererTldr,
+		Theme:            ThemeConfig{Base: "default"},
+		Separator:        defaultSeparator,
+		Header:           defaultHeader,
+		AutoBootstrap:    true,
+		SizeCap:          DefaultSizeCap,
+		CacheMaxAge:      DefaultCacheMaxAge,
+		MinMatch:         DefaultMinMatch,
+		WarnShadows:      true,
+		Confirm:          ConfirmSmart,
+		PickMRU:          true,
+		Hints:            true,
+		DirMode:          DefaultDirMode,
+		FileMode:         DefaultFileMode,
+	}
+
+	explicit := len(configPaths) > 0
+	if !explicit {
+		filenames := configFilenames(profile)
+		configPath := filepath.Join(cheatSheetDir, filenames[0])
+		if ok, _ := IsFileExists(cheatSheetDir, filenames[0]); !ok {
+			if ok, _ := IsFileExists(cheatSheetDir, filenames[1]); ok {
+				configPath = filepath.Join(cheatSheetDir, filenames[1])
+			}
+		}
+		configPaths = []string{configPath}
+	}
+
+	cfg.Layers = configPaths[:len(configPaths)-1]
+	cfg.Path = configPaths[len(configPaths)-1]
+	for _, configPath := range configPaths {
+		if err := cfg.Load(configPath); err != nil {
+			if errors.Is(err, ErrInvalidConfig) {
+				// Still return the config so that it can be fixed with --edit-config.
+				return cfg, err
+			}
+
+			if !errors.Is(err, os.ErrNotExist) {
+				return nil, readOnlyHint(err)
+			}
+
+			if explicit || profile != "" {
+				warnf("config file '%v' doesn't exist", configPath)
+			}
+		}
+	}
+
+	if cacheDir := os.Getenv(CacheDirEnv); cacheDir != "" {
+		cfg.TldrCachePath = cacheDir
+		cfg.CacheLayout = LayoutTldr
+	} else {
+		cfg.detectCacheLayout(dirname)
+	}
+
+	// Created once the config is loaded to honor its dir_mode.
+	if err := EnsureDir(cheatSheetDir, cfg.DirPerm()); err != nil {
+		return nil, readOnlyHint(err)
+	}
+
+	if cfg.CheatSheetsDir != cheatSheetDir {
+		if err := EnsureDir(cfg.CheatSheetsDir, cfg.DirPerm()); err != nil {
+			return nil, readOnlyHint(err)
+		}
+	}
+
+	return cfg, nil
+}
+
+type Config struct {
+	CheatSheetsDir string `yaml:"dir" toml:"dir"`
+	// DataDir stores the state of the tool, e.g. downloaded cheat-sheets.
+	DataDir       string   `yaml:"data_dir" toml:"data_dir"`
+	TldrPath      string   `yaml:"tldr_path" toml:"tldr_path"`
+	TldrCachePath string   `yaml:"tldr_cache_path" toml:"tldr_cache_path"`
+	TldrPages     []string `yaml:"tldr_pages" toml:"tldr_pages"`
+	// Lang is the language of tldr pages found without -lang, e.g. zh,
+	// english when empty.
+	Lang string `yaml:"lang,omitempty" toml:"lang,omitempty"`
+	// TldrNotFoundCode is the exit code of the tldr client for pages it
+	// doesn't find.
+	TldrNotFoundCode int `yaml:"tldr_not_found_code" toml:"tldr_not_found_code"`
+	// TldrArgs are appended to every run of the tldr client.
+	TldrArgs   []string `yaml:"tldr_args,omitempty" toml:"tldr_args,omitempty"`
+	EditorPath string   `yaml:"editor" toml:"editor"`
+	// CheatSheetEditor edits and views cheat-sheets instead of the editor,
+	// and Editors instead of both for cheat-sheets of their extension.
+	CheatSheetEditor string            `yaml:"cheatsheet_editor,omitempty" toml:"cheatsheet_editor,omitempty"`
+	Editors          map[string]string `yaml:"editors,omitempty" toml:"editors,omitempty"`
+	Extension        string            `yaml:"extension" toml:"extension"`
+	// Renderer of cheat-sheets, `tldr`, `native` or `bat`.
+	Renderer string      `yaml:"renderer" toml:"renderer"`
+	Theme    ThemeConfig `yaml:"theme" toml:"theme"`
+	// Substitutions replace tokens of local cheat-sheets when rendered.
+	Substitutions map[string]string `yaml:"substitutions,omitempty" toml:"substitutions,omitempty"`
+	// Separator and Header delimit cheat-sheets output one after another.
+	// Header is a text/template given the Topic, Path and Source of the cheat-sheet.
+	Separator string `yaml:"separator" toml:"separator"`
+	Header    string `yaml:"header" toml:"header"`
+	// AutoBootstrap populates an empty tldr cache before the first find.
+	AutoBootstrap bool `yaml:"auto_bootstrap" toml:"auto_bootstrap"`
+	// SizeCap in bytes is the advisory size of cheat-sheets, above which they
+	// are likely saved by mistake. 0 disables it.
+	SizeCap int64 `yaml:"size_cap" toml:"size_cap"`
+	// CacheMaxAge in days is the age of the tldr cache above which it is
+	// reported stale. 0 disables it.
+	CacheMaxAge int `yaml:"cache_max_age" toml:"cache_max_age"`
+	// FallbackCommand is run with $SHELL for topics found nowhere, unless
+	// offline. It is a text/template given the Topic.
+	FallbackCommand string `yaml:"fallback_command" toml:"fallback_command"`
+	// MinMatch is the similarity, from 0 to 1, of topics suggested for a
+	// topic found nowhere.
+	MinMatch float64 `yaml:"min_match" toml:"min_match"`
+	// WarnShadows warns once per topic when a local cheat-sheet found shadows
+	// a page of the tldr cache.
+	WarnShadows bool `yaml:"warn_shadows" toml:"warn_shadows"`
+	// Confirm is when destructive operations are confirmed: always, never or
+	// smart, only for those affecting existing files.
+	Confirm string `yaml:"confirm" toml:"confirm"`
+	// PickMRU starts the `--pick` picker from the most recently used topic,
+	// so Enter opens it again.
+	PickMRU bool `yaml:"pick_mru" toml:"pick_mru"`
+	// Hints prints a footer after a tldr page shown for lack of a local
+	// cheat-sheet, telling how to customize it.
+	Hints bool `yaml:"hints" toml:"hints"`
+	// StripFrontMatter strips the front-matter of `--no-render` output.
+	StripFrontMatter bool `yaml:"strip_front_matter" toml:"strip_front_matter"`
+	// TopicDefaults are defaults of the flags finding a topic, by topic.
+	TopicDefaults map[string]TopicDefaults `yaml:"topic_defaults,omitempty" toml:"topic_defaults,omitempty"`
+	// FsyncState syncs state files of the data directory to disk when
+	// written, so they survive a system crash, at some cost in speed.
+	FsyncState bool `yaml:"fsync_state" toml:"fsync_state"`
+	// DirMode and FileMode are the octal permissions of the directories and
+	// files created, e.g. `0700` and `0600` to keep cheat-sheets private.
+	DirMode  string `yaml:"dir_mode" toml:"dir_mode"`
+	FileMode string `yaml:"file_mode" toml:"file_mode"`
+
+	// CacheLayout is the layout of the tldr cache detected, `tldr` or
+	// `tealdeer` when only a tealdeer cache was found.
+	CacheLayout string `yaml:"-" toml:"-"`
+	// Path is the config file, loaded if it exists.
+	Path string `yaml:"-" toml:"-"`
+	// Layers are config files loaded before Path, which overrides them.
+	Layers []string `yaml:"-" toml:"-"`
+	// UnknownKeys are keys of the config file not matching any setting.
+	UnknownKeys []string `yaml:"-" toml:"-"`
+}
+
+// Load overrides the config with values found in the given yaml or toml
+// file, picked by its extension.
+func (c *Config) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch filepath.Ext(path) {
+	case ".toml":
+		meta, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("%w '%v': %v", ErrInvalidConfig, path, err)
+		}
+
+		for _, key := range meta.Undecoded() {
+			c.UnknownKeys = append(c.UnknownKeys, key.String())
+		}
+	default:
+		if err := yaml.Unmarshal(data, c); err != nil {
+			return fmt.Errorf("%w '%v': %v", ErrInvalidConfig, path, err)
+		}
+
+		var keys map[string]any
+		if err := yaml.Unmarshal(data, &keys); err != nil {
+			return fmt.Errorf("%w '%v': %v", ErrInvalidConfig, path, err)
+		}
+
+		known := configKeys()
+		for key := range keys {
+			if !known[key] {
+				c.UnknownKeys = append(c.UnknownKeys, key)
+			}
+		}
+
+		topicKeys := yamlKeys(reflect.TypeOf(TopicDefaults{}))
+		topics, _ := keys["topic_defaults"].(map[string]any)
+		for topic, val := range topics {
+			defaults, _ := val.(map[string]any)
+			for key := range defaults {
+				if !topicKeys[key] {
+					c.UnknownKeys = append(c.UnknownKeys, "topic_defaults."+topic+"."+key)
+				}
+			}
+		}
+		sort.Strings(c.UnknownKeys)
+	}
+
+	c.Extension = strings.TrimPrefix(c.Extension, ".")
+	if c.Extension == "" {
+		c.Extension = DefaultExtension
+	}
+
+	switch c.Confirm {
+	case "":
+		c.Confirm = ConfirmSmart
+	case ConfirmAlways, ConfirmNever, ConfirmSmart:
+	default:
+		return fmt.Errorf("%w '%v': confirm '%v' is not one of always, never, smart", ErrInvalidConfig, path, c.Confirm)
+	}
+
+	for topic, defaults := range c.TopicDefaults {
+		switch defaults.Source {
+		case "", SourceLocal, SourceTldr:
+		default:
+			return fmt.Errorf("%w '%v': source '%v' of topic '%v' is not one of local, tldr", ErrInvalidConfig, path, defaults.Source, topic)
+		}
+	}
+
+	// Modes left out keep the defaults, see DirPerm and FilePerm.
+	for _, mode := range [][2]string{{"dir_mode", c.DirMode}, {"file_mode", c.FileMode}} {
+		if _, err := parseMode(mode[1]); err != nil && mode[1] != "" {
+			return fmt.Errorf("%w '%v': %v '%v' is not an octal permission", ErrInvalidConfig, path, mode[0], mode[1])
+		}
+	}
+	return nil
+}
+
+func parseMode(mode string) (os.FileMode, error) {
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || perm > 0777 {
+		return 0, fmt.Errorf("invalid permission '%v'", mode)
+	}
+	return os.FileMode(perm), nil
+}
+
+// DirPerm returns the permission of created directories.
+func (c *Config) DirPerm() os.FileMode {
+	perm, err := parseMode(c.DirMode)
+	if err != nil {
+		perm, _ = parseMode(DefaultDirMode)
+	}
+	return perm
+}
+
+// FilePerm returns the permission of created files.
+func (c *Config) FilePerm() os.FileMode {
+	perm, err := parseMode(c.FileMode)
+	if err != nil {
+		perm, _ = parseMode(DefaultFileMode)
+	}
+	return perm
+}
+
+// configKeys returns the top-level keys of the config file.
+func configKeys() map[string]bool {
+	return yamlKeys(reflect.TypeOf(Config{}))
+}
+
+// yamlKeys returns the yaml keys of the fields of the struct type.
+func yamlKeys(t reflect.Type) map[string]bool {
+	keys := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
+
+// TopicDefaults are flags applied to finding a topic unless given: Source
+// finds only the local cheat-sheet, or only the tldr page, and Lang is the
+// language of the tldr page.
+type TopicDefaults struct {
+	Source string `yaml:"source,omitempty" toml:"source,omitempty"`
+	Lang   string `yaml:"lang,omitempty" toml:"lang,omitempty"`
+}
+
+// TopicDefaultsFor returns the defaults of the topic, matched the way topics
+// are, so `git commit` and `git-commit` are the same.
+func (c *Config) TopicDefaultsFor(topic string) (TopicDefaults, bool) {
+	for key, defaults := range c.TopicDefaults {
+		if NormalizeTopic(key) == topic {
+			return defaults, true
+		}
+	}
+	return TopicDefaults{}, false
+}
+
+// Extensions returns the file extensions recognized for local cheat-sheets,
+// the configured one first.
+func (c *Config) Extensions() []string {
+	if c.Extension == DefaultExtension {
+		return []string{DefaultExtension}
+	}
+	return []string{c.Extension, DefaultExtension}
+}
+
+// EditorFor returns the editor of the cheat-sheet file: the one of its
+// extension, else the cheat-sheet editor, else the editor.
+func (c *Config) EditorFor(path string) string {
+	ext := strings.TrimPrefix(filepath.Ext(strings.TrimSuffix(path, EncryptedExt)), ".")
+	if editor := c.Editors[ext]; editor != "" {
+		return editor
+	}
+
+	if c.CheatSheetEditor != "" {
+		return c.CheatSheetEditor
+	}
+	return c.EditorPath
+}
+
+// Oversized reports whether a cheat-sheet of the size exceeds the size cap.
+func (c *Config) Oversized(size int64) bool {
+	return c.SizeCap > 0 && size > c.SizeCap
+}
+
+// EnsureDir creates the directory with the permission if it doesn't exist.
+// A symlink to a directory, e.g. a synced folder, is used as is, and a
+// dangling symlink is reported rather than replaced.
+func EnsureDir(dirname string, perm os.FileMode) error {
+	info, err := os.Stat(dirname)
+	if err == nil {
+		if !info.IsDir() {
+			return fmt.Errorf("'%v' is not a directory", dirname)
+		}
+		return nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if linkInfo, err := os.Lstat(dirname); err == nil && linkInfo.Mode()&os.ModeSymlink != 0 {
+		target, _ := os.Readlink(dirname)
+		return fmt.Errorf("'%v' is a symlink to missing '%v'", dirname, target)
+	}
+
+	return os.Mkdir(dirname, perm)
+}
+
+// ConfigTemplate returns the config as a config file in the format of path,
+// with every setting commented out, as a starting point to edit.
+func ConfigTemplate(cfg *Config, path string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if filepath.Ext(path) == ".toml" {
+		err = toml.NewEncoder(&buf).Encode(cfg)
+	} else {
+		err = yaml.NewEncoder(&buf).Encode(cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("# cheat-sheet config, uncomment and change settings to override their defaults.\n")
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		b.WriteString("# " + line + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// EditConfig opens the config file in the editor, creating it from a
+// template of all settings first if needed, then checks it still parses.
+func (e *Executor) EditConfig(cmd *Command) error {
+	path := e.cfg.Path
+	ok, err := IsFileExists(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		data, err := ConfigTemplate(e.cfg, path)
+		if err != nil {
+			return err
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), e.cfg.DirPerm()); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, data, e.cfg.FilePerm()); err != nil {
+			return err
+		}
+	}
+
+	if err := e.editFile(cmd, e.cfg.EditorPath, path); err != nil {
+		return err
+	}
+
+	var cfg Config
+	if err := cfg.Load(path); err != nil {
+		return fmt.Errorf("config is invalid, fix it with 'cs --edit-config': %w", err)
+	}
+
+	for _, key := range cfg.UnknownKeys {
+		warnf("unknown config key '%v'", key)
+	}
+	return nil
+}
+
+// ListProfiles prints the profiles having a config file beside the one in
+// use.
+func (e *Executor) ListProfiles() error {
+	profiles, err := Profiles(filepath.Dir(e.cfg.Path))
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range profiles {
+		fmt.Println(profile)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// symlinkedTempDir returns a temporary directory reached through a
+// symlink, like /var being /private/var on macOS.
+func symlinkedTempDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	link := filepath.Join(t.TempDir(), "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+	return link
+}
+
+func TestEnsureDir(t *testing.T) {
+	base := symlinkedTempDir(t)
+	if err := os.WriteFile(filepath.Join(base, "file"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(base, "missing"), filepath.Join(base, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		wantErr string
+	}{
+		{"symlinked existing dir", base, ""},
+		{"missing dir under a symlink", filepath.Join(base, "new"), ""},
+		{"file", filepath.Join(base, "file"), "is not a directory"},
+		{"dangling symlink", filepath.Join(base, "dangling"), "is a symlink to missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EnsureDir(tt.dir, 0755)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("EnsureDir failed: %v", err)
+				}
+				if info, err := os.Stat(tt.dir); err != nil || !info.IsDir() {
+					t.Errorf("'%v' isn't a directory: %v", tt.dir, err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("EnsureDir = %v, want an error with %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnsureDirKeepsSymlink(t *testing.T) {
+	link := symlinkedTempDir(t)
+	if err := EnsureDir(link, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("'%v' is no longer a symlink: %v", link, err)
+	}
+}
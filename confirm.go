@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+const (
+	// ConfirmAlways prompts before every destructive operation.
+	ConfirmAlways = "always"
+	// ConfirmNever never prompts, as if answered yes.
+	ConfirmNever = "never"
+	// ConfirmSmart prompts only for operations affecting existing files.
+	ConfirmSmart = "smart"
+)
+
+// ttyPath is the terminal prompts read from, whatever stdin is.
+const ttyPath = "/dev/tty"
+
+// Prompter asks questions on the terminal.
+type Prompter struct {
+	tty    *os.File
+	reader *bufio.Reader
+}
+
+func OpenPrompter() (*Prompter, error) {
+	tty, err := os.OpenFile(ttyPath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Prompter{
+		tty:    tty,
+		reader: bufio.NewReader(tty),
+	}, nil
+}
+
+// Ask prints the question and returns the answer, trimmed, or def if empty.
+func (p *Prompter) Ask(def, format string, args ...any) string {
+	fmt.Fprintf(p.tty, format, args...)
+	answer, _ := p.reader.ReadString('\n')
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return def
+	}
+	return answer
+}
+
+// AskYes asks a yes or no question, def being the answer if empty.
+func (p *Prompter) AskYes(def bool, format string, args ...any) bool {
+	hint := " [y/N] "
+	if def {
+		hint = " [Y/n] "
+	}
+
+	switch strings.ToLower(p.Ask("", format+hint, args...)) {
+	case "y", "yes":
+		return true
+	case "":
+		return def
+	}
+	return false
+}
+
+func (p *Prompter) Close() error {
+	return p.tty.Close()
+}
+
+// confirm asks the user whether to go ahead with the operation, per the
+// confirm config: existing tells whether it affects existing files. Without
+// a terminal to prompt on, the answer is no.
+func (e *Executor) confirm(existing bool, format string, args ...any) bool {
+	switch e.cfg.Confirm {
+	case ConfirmNever:
+		return true
+	case ConfirmAlways:
+	default:
+		if !existing {
+			return true
+		}
+	}
+
+	p, err := OpenPrompter()
+	if err != nil {
+		if e.printLog {
+			log.Printf("no terminal to confirm, assume no: %v\n", err)
+		}
+		return false
+	}
+	defer p.Close()
+
+	return p.AskYes(false, format, args...)
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+const (
+	// EncryptedExt marks encrypted cheat-sheets, e.g. `secrets.md.enc`.
+	EncryptedExt  = ".enc"
+	PassphraseEnv = "CHEAT_SHEET_PASSPHRASE"
+
+	encryptedMagic = "cheat-sheet-enc1\n"
+	saltSize       = 16
+)
+
+var ErrDecrypt = errors.New("wrong passphrase or corrupted cheat-sheet")
+
+func IsEncrypted(path string) bool {
+	return strings.HasSuffix(path, EncryptedExt)
+}
+
+func deriveKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt seals the plaintext with AES-GCM, keyed by scrypt from the
+// passphrase and a random salt stored in the output with the nonce.
+func Encrypt(plaintext []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte(encryptedMagic), salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, plaintext, []byte(encryptedMagic)), nil
+}
+
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(encryptedMagic)) {
+		return nil, ErrDecrypt
+	}
+	data = data[len(encryptedMagic):]
+
+	if len(data) < saltSize {
+		return nil, ErrDecrypt
+	}
+
+	aead, err := deriveKey(passphrase, data[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	data = data[saltSize:]
+
+	if len(data) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(encryptedMagic))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// passphrase returns $CHEAT_SHEET_PASSPHRASE, or else prompts for it once,
+// twice when confirming a new one.
+func (e *Executor) passphrase(confirm bool) (string, error) {
+	if e.cachedPassphrase != "" {
+		return e.cachedPassphrase, nil
+	}
+
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	if e.plain || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("no terminal to prompt for the passphrase, set %v", PassphraseEnv)
+	}
+
+	passphrase, err := promptPassword("passphrase: ")
+	if err != nil {
+		return "", err
+	}
+
+	if confirm {
+		again, err := promptPassword("confirm passphrase: ")
+		if err != nil {
+			return "", err
+		}
+
+		if again != passphrase {
+			return "", errors.New("passphrases don't match")
+		}
+	}
+
+	if passphrase == "" {
+		return "", errors.New("empty passphrase")
+	}
+
+	e.cachedPassphrase = passphrase
+	return passphrase, nil
+}
+
+func promptPassword(prompt string) (string, error) {
+	// Turn echo back on should the tool exit while reading.
+	fd := int(os.Stdin.Fd())
+	if state, err := terminals.GetState(fd); err == nil {
+		defer restoreOnExit(func() { terminals.Restore(fd, state) })()
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	data, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	return string(data), err
+}
+
+// readSheet returns the content of the cheat-sheet, decrypted if needed.
+func (e *Executor) readSheet(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !IsEncrypted(path) {
+		return data, err
+	}
+
+	passphrase, err := e.passphrase(false)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := Decrypt(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt '%v': %w", path, err)
+	}
+	return plaintext, nil
+}
+
+// editEncrypted edits the decrypted cheat-sheet in a private temporary file,
+// then encrypts it back if it changed.
+func (e *Executor) editEncrypted(cmd *Command, path string) error {
+	plaintext, err := e.readSheet(path)
+	if err != nil {
+		return err
+	}
+
+	dirname, err := os.MkdirTemp("", "cheat-sheet-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dirname)
+
+	tmp := filepath.Join(dirname, strings.TrimSuffix(filepath.Base(path), EncryptedExt))
+	if err := os.WriteFile(tmp, plaintext, 0600); err != nil {
+		return err
+	}
+
+	if err := e.editLocalCheatSheet(cmd, tmp); err != nil {
+		return err
+	}
+
+	edited, err := os.ReadFile(tmp)
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(edited, plaintext) {
+		return nil
+	}
+	return e.saveSheet(path, edited)
+}
+
+// saveSheet writes the content of the cheat-sheet, encrypted if it's an
+// encrypted one.
+func (e *Executor) saveSheet(path string, content []byte) error {
+	if !IsEncrypted(path) {
+		return WriteFileAtomic(path, content, e.cfg.FilePerm())
+	}
+
+	passphrase, err := e.passphrase(false)
+	if err != nil {
+		return err
+	}
+
+	data, err := Encrypt(content, passphrase)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, data, e.cfg.FilePerm())
+}
+
+// Encrypt replaces the plain local cheat-sheet by an encrypted one.
+func (e *Executor) Encrypt(cmd *Command) error {
+	if err := requireTopic(cmd, "cs --encrypt <name>"); err != nil {
+		return err
+	}
+
+	path, err := e.findLocalCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+
+	if IsEncrypted(path) {
+		return fmt.Errorf("cheat-sheet '%v' is already encrypted", cmd.Topic())
+	}
+
+	plaintext, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	passphrase, err := e.passphrase(true)
+	if err != nil {
+		return err
+	}
+
+	data, err := Encrypt(plaintext, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if err := WriteFileAtomic(path+EncryptedExt, data, e.cfg.FilePerm()); err != nil {
+		return err
+	}
+
+	fmt.Printf("encrypted '%v' to '%v'\n", cmd.Topic(), path+EncryptedExt)
+	return os.Remove(path)
+}
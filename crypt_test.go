@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeEncryptedSheet writes the cheat-sheet encrypted with the passphrase of
+// $CHEAT_SHEET_PASSPHRASE.
+func writeEncryptedSheet(t *testing.T, cfg *Config, topic, content string) string {
+	t.Helper()
+	data, err := Encrypt([]byte(content), os.Getenv(PassphraseEnv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(cfg.CheatSheetsDir, topic+"."+cfg.Extension+EncryptedExt)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func decryptFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := Decrypt(data, os.Getenv(PassphraseEnv))
+	if err != nil {
+		t.Fatalf("decrypt '%v': %v", path, err)
+	}
+	return string(plaintext)
+}
+
+func TestMergeIntoEncrypted(t *testing.T) {
+	t.Setenv(PassphraseEnv, "secret")
+	cfg := testConfig(t)
+	dest := writeEncryptedSheet(t, cfg, "vault", "# vault\n\n- Login:\n\n`vault login`\n")
+	writeSheet(t, cfg, "vault-extra", "# vault-extra\n\n- Read a secret:\n\n`vault read {{path}}`\n")
+
+	cmd := NewCommand(CmdMerge, WithArgs([]string{"vault-extra", "vault"}))
+	captureStdout(t, func() error {
+		return NewExecutor(cfg, WithTldr(&fakeTldr{})).Exec(context.Background(), cmd)
+	})
+
+	got := decryptFile(t, dest)
+	for _, want := range []string{"`vault login`", "`vault read {{path}}`"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("merged %q, want %v in it", got, want)
+		}
+	}
+
+	if _, err := os.Stat(strings.TrimSuffix(dest, EncryptedExt)); !os.IsNotExist(err) {
+		t.Errorf("plain copy of the encrypted cheat-sheet written: %v", err)
+	}
+}
+
+func TestSearchEncrypted(t *testing.T) {
+	t.Setenv(PassphraseEnv, "secret")
+	cfg := testConfig(t)
+	writeEncryptedSheet(t, cfg, "vault", "# vault\n\n- Login:\n\n`vault login`\n")
+
+	cmd := NewCommand(CmdSearch, WithArgs([]string{"login"}))
+	got := captureStdout(t, func() error {
+		return NewExecutor(cfg, WithTldr(&fakeTldr{})).Exec(context.Background(), cmd)
+	})
+
+	if want := "vault:3: - Login:\nvault:5: `vault login`\n"; got != want {
+		t.Errorf("searched %q, want %q", got, want)
+	}
+}
+
+func TestEncryptedMetadataNotRead(t *testing.T) {
+	t.Setenv(PassphraseEnv, "secret")
+	cfg := testConfig(t)
+	path := writeEncryptedSheet(t, cfg, "vault", "---\ndescription: Secrets\ntags: ops\n---\n# vault\n")
+
+	if desc, err := ParseDescription(path); err != nil || desc != "" {
+		t.Errorf("ParseDescription = %q, %v, want none", desc, err)
+	}
+
+	if ok, err := HasTag(path, "ops"); err != nil || ok {
+		t.Errorf("HasTag = %v, %v, want false", ok, err)
+	}
+}
+
+func TestRenameAllKeepsEncrypted(t *testing.T) {
+	t.Setenv(PassphraseEnv, "secret")
+	cfg := testConfig(t)
+	writeEncryptedSheet(t, cfg, "vault", "# vault\n")
+
+	renames := filepath.Join(t.TempDir(), "renames.txt")
+	if err := os.WriteFile(renames, []byte("vault -> secrets\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewCommand(CmdRenameAll, WithArgs([]string{renames}))
+	captureStdout(t, func() error {
+		return NewExecutor(cfg, WithTldr(&fakeTldr{})).Exec(context.Background(), cmd)
+	})
+
+	sheets, err := LocalCheatSheets(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := filepath.Join(cfg.CheatSheetsDir, "secrets.md"+EncryptedExt)
+	if len(sheets) != 1 || sheets[0].Topic != "secrets" || sheets[0].Path != want {
+		t.Errorf("cheat-sheets %v, want secrets at '%v'", sheets, want)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const diffContext = 3
+
+type diffOp struct {
+	kind byte // ' ', '-' or '+'
+	line string
+	// a and b are the line numbers, from 0, in each side before the op.
+	a, b int
+}
+
+// diffLines returns the edit script from a to b, going by their longest
+// common subsequence. Cheat-sheets are short enough for the quadratic table.
+func diffLines(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i, j})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, diffOp{'+', b[j], i, j})
+			j++
+		default:
+			ops = append(ops, diffOp{'-', a[i], i, j})
+			i++
+		}
+	}
+	return ops
+}
+
+// UnifiedDiff returns the unified diff from the a to the b content, empty if
+// they are the same.
+func UnifiedDiff(aName, bName, a, b string) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	for start := 0; start < len(ops); {
+		// Find the next change, then extend the hunk while changes are
+		// within twice the context of each other.
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+
+		last := first
+		for k := first; k < len(ops) && k <= last+2*diffContext; k++ {
+			if ops[k].kind != ' ' {
+				last = k
+			}
+		}
+
+		from := first - diffContext
+		if from < start {
+			from = start
+		}
+
+		to := last + diffContext + 1
+		if to > len(ops) {
+			to = len(ops)
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+		}
+		writeHunk(&out, ops[from:to])
+		start = to
+	}
+	return out.String()
+}
+
+func writeHunk(w *strings.Builder, ops []diffOp) {
+	aLen, bLen := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			aLen++
+		}
+		if op.kind != '-' {
+			bLen++
+		}
+	}
+
+	fmt.Fprintf(w, "@@ -%s +%s @@\n", hunkRange(ops[0].a, aLen), hunkRange(ops[0].b, bLen))
+	for _, op := range ops {
+		w.WriteByte(op.kind)
+		w.WriteString(op.line)
+		w.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the range of a hunk side the way diff -u does: an empty
+// range is given by the line before it.
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if n == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// DiffCache prints the unified diff of the pages of the topic in two page
+// directories of the tldr cache, e.g. common and linux.
+func (e *Executor) DiffCache(cmd *Command) error {
+	if len(cmd.Args) < 3 {
+		return fmt.Errorf("expect a topic and two page directories, e.g. 'cs --diff-cache tar common linux'")
+	}
+
+	n := len(cmd.Args)
+	topic := NormalizeTopic(cmd.Args[:n-2]...)
+	pages := cmd.Args[n-2:]
+
+	var contents [2]string
+	var names [2]string
+	for i, page := range pages {
+		dir := filepath.Join(e.cfg.TldrCachePath, page)
+		name, err := FindFileFold(dir, topic+"."+DefaultExtension)
+		if err != nil {
+			return err
+		}
+
+		if name == "" {
+			return fmt.Errorf("%w: '%v' in the '%v' pages of the tldr cache", ErrNotFound, topic, page)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		contents[i] = string(data)
+		names[i] = filepath.Join(page, name)
+	}
+
+	fmt.Print(UnifiedDiff(names[0], names[1], contents[0], contents[1]))
+	return nil
+}
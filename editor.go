@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// readOnlyArgs maps common editors to the arguments opening a file
+// read-only.
+var readOnlyArgs = map[string][]string{
+	"vi":    {"-R"},
+	"vim":   {"-R"},
+	"nvim":  {"-R"},
+	"gvim":  {"-R"},
+	"nano":  {"-v"},
+	"micro": {"-readonly", "true"},
+	"kak":   {"-ro"},
+}
+
+// EditorName returns the name of the editor binary without directory and
+// extension, e.g. `vim` for `/usr/bin/vim`.
+func EditorName(editorPath string) string {
+	name := filepath.Base(editorPath)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// ReadOnlyArgs returns the arguments to open path read-only in the editor.
+// Unknown editors just get the path.
+func ReadOnlyArgs(editorPath, path string) []string {
+	args := readOnlyArgs[EditorName(editorPath)]
+	return append(append([]string{}, args...), path)
+}
+
+// gotoArgs maps common editors to the arguments opening a file at a line.
+var gotoArgs = map[string]func(path string, line int) []string{
+	"vi":    plusLineArgs,
+	"vim":   plusLineArgs,
+	"nvim":  plusLineArgs,
+	"gvim":  plusLineArgs,
+	"nano":  plusLineArgs,
+	"emacs": plusLineArgs,
+	"kak":   plusLineArgs,
+	"micro": plusLineArgs,
+	"code": func(path string, line int) []string {
+		return []string{"--goto", fmt.Sprintf("%s:%d", path, line)}
+	},
+	"subl": colonLineArgs,
+	"hx":   colonLineArgs,
+}
+
+func plusLineArgs(path string, line int) []string {
+	return []string{fmt.Sprintf("+%d", line), path}
+}
+
+func colonLineArgs(path string, line int) []string {
+	return []string{fmt.Sprintf("%s:%d", path, line)}
+}
+
+// GotoArgs returns the arguments to open path at the line in the editor.
+// Unknown editors just get the path, opening it at the top.
+func GotoArgs(editorPath, path string, line int) []string {
+	args, ok := gotoArgs[EditorName(editorPath)]
+	if !ok || line <= 0 {
+		return []string{path}
+	}
+	return args(path, line)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// LineLimiter writes only the first lines written to it when head, or else
+// only the last ones, once closed. Lines are kept whole, so the color
+// escapes of rendered output stay as they were.
+type LineLimiter struct {
+	w     io.Writer
+	n     int
+	head  bool
+	count int
+	// partial is the last line written to it, not ended by a newline yet.
+	partial []byte
+	// tail are the last n lines, the oldest first.
+	tail [][]byte
+}
+
+func NewLineLimiter(w io.Writer, n int, head bool) *LineLimiter {
+	return &LineLimiter{w: w, n: n, head: head}
+}
+
+func (l *LineLimiter) Write(p []byte) (int, error) {
+	rest := p
+	for len(rest) > 0 {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			l.partial = append(l.partial, rest...)
+			break
+		}
+
+		line := append(l.partial, rest[:i+1]...)
+		l.partial = nil
+		rest = rest[i+1:]
+		if err := l.line(line); err != nil {
+			return len(p) - len(rest), err
+		}
+	}
+	return len(p), nil
+}
+
+func (l *LineLimiter) line(line []byte) error {
+	l.count++
+	if l.head {
+		if l.count > l.n {
+			return nil
+		}
+		_, err := l.w.Write(line)
+		return err
+	}
+
+	l.tail = append(l.tail, line)
+	if len(l.tail) > l.n {
+		l.tail = l.tail[1:]
+	}
+	return nil
+}
+
+// Close writes the last line, if not ended by a newline, and the kept lines
+// of the tail.
+func (l *LineLimiter) Close() error {
+	if len(l.partial) > 0 {
+		if err := l.line(l.partial); err != nil {
+			return err
+		}
+		l.partial = nil
+	}
+
+	for _, line := range l.tail {
+		if _, err := l.w.Write(line); err != nil {
+			return err
+		}
+	}
+	l.tail = nil
+	return nil
+}
+
+// limitOutput redirects stdout through a LineLimiter when --head or --tail
+// is given, so that every way of rendering is limited alike, tldr included.
+// The returned function restores stdout, writing what's left; it's a no-op
+// without either flag.
+func (e *Executor) limitOutput(cmd *Command) (func() error, error) {
+	if !cmd.HasFlag(HeadFlag) && !cmd.HasFlag(TailFlag) {
+		return func() error { return nil }, nil
+	}
+
+	if cmd.HasFlag(HeadFlag) && cmd.HasFlag(TailFlag) {
+		return nil, errors.New("--head and --tail can't be combined")
+	}
+
+	name := HeadFlag
+	if cmd.HasFlag(TailFlag) {
+		name = TailFlag
+	}
+
+	n, err := cmd.IntFlag(name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid -%v '%v': must be positive", name, n)
+	}
+
+	// Stdout turns into a pipe, so decide on colors while it's the terminal.
+	if UseColor(e.color) {
+		e.color = ColorAlways
+		os.Setenv("FORCE_COLOR", "1")
+	}
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	os.Stdout = w
+
+	limiter := NewLineLimiter(stdout, n, name == HeadFlag)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(limiter, r)
+		r.Close()
+		done <- err
+	}()
+
+	return func() error {
+		os.Stdout = stdout
+		w.Close()
+		err := <-done
+		if closeErr := limiter.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeTldr is a TldrClient serving pages from memory and recording how it's
+// called.
+type fakeTldr struct {
+	// pages are the contents of the pages by topic, e.g. `git-commit`.
+	pages map[string]string
+	// cachePath, if set, holds the pages as common ones of a tldr cache.
+	cachePath string
+
+	finds    [][]string
+	rendered []string
+	updates  int
+	// pageUpdates are the page sets updated alone, when canUpdatePage.
+	pageUpdates   []string
+	canUpdatePage bool
+	// onUpdate, if set, runs on every update, e.g. to change the cache.
+	onUpdate func() error
+}
+
+func (f *fakeTldr) Find(ctx context.Context, args ...string) error {
+	_, err := f.FindPage(ctx, args...)
+	return err
+}
+
+func (f *fakeTldr) FindPage(ctx context.Context, args ...string) (bool, error) {
+	f.finds = append(f.finds, args)
+	_, ok := f.pages[NormalizeTopic(args...)]
+	return ok, nil
+}
+
+// Render records the content rendered, as the file may be temporary.
+func (f *fakeTldr) Render(ctx context.Context, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	f.rendered = append(f.rendered, string(data))
+	return nil
+}
+
+func (f *fakeTldr) Update(ctx context.Context) error {
+	f.updates++
+	if f.onUpdate != nil {
+		return f.onUpdate()
+	}
+	return nil
+}
+
+func (f *fakeTldr) UpdatePage(ctx context.Context, page string) (bool, error) {
+	if !f.canUpdatePage {
+		return false, nil
+	}
+
+	f.pageUpdates = append(f.pageUpdates, page)
+	if f.onUpdate != nil {
+		return true, f.onUpdate()
+	}
+	return true, nil
+}
+
+func (f *fakeTldr) Version(ctx context.Context) (string, error) {
+	return "1.0.0", nil
+}
+
+func (f *fakeTldr) FindFileInCache(filename string) (string, error) {
+	topic := strings.TrimSuffix(filename, "."+DefaultExtension)
+	if _, ok := f.pages[topic]; !ok || f.cachePath == "" {
+		return "", nil
+	}
+	return filepath.Join(f.cachePath, "common", filename), nil
+}
+
+func (f *fakeTldr) FindLocalizedFileInCache(lang, filename string) (string, error) {
+	return "", nil
+}
+
+func (f *fakeTldr) SetPrintLog(printLog bool) {}
+
+func (f *fakeTldr) SetStdout(w io.Writer) {}
+
+// testConfig returns a config of temporary directories, with a non-empty
+// tldr cache so that it isn't bootstrapped.
+func testConfig(t *testing.T) *Config {
+	t.Helper()
+	cache := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(cache, "common"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	return &Config{
+		CheatSheetsDir: t.TempDir(),
+		DataDir:        t.TempDir(),
+		TldrCachePath:  cache,
+		TldrPages:      []string{"common"},
+		Extension:      DefaultExtension,
+		Renderer:       RendererTldr,
+		Confirm:        ConfirmNever,
+		DirMode:        DefaultDirMode,
+		FileMode:       DefaultFileMode,
+	}
+}
+
+// cachedTldr returns a fakeTldr serving the pages, also written to the tldr
+// cache of the config.
+func cachedTldr(t *testing.T, cfg *Config, pages map[string]string) *fakeTldr {
+	t.Helper()
+	for topic, content := range pages {
+		path := filepath.Join(cfg.TldrCachePath, "common", topic+"."+DefaultExtension)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &fakeTldr{pages: pages, cachePath: cfg.TldrCachePath}
+}
+
+func writeSheet(t *testing.T, cfg *Config, topic, content string) string {
+	t.Helper()
+	path := filepath.Join(cfg.CheatSheetsDir, topic+"."+cfg.Extension)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExecFindLocal(t *testing.T) {
+	cfg := testConfig(t)
+	writeSheet(t, cfg, "git", "# git\n\n- Status:\n\n`git status`\n")
+	tldr := &fakeTldr{pages: map[string]string{"git": "# git (tldr)"}}
+
+	err := NewExecutor(cfg, WithTldr(tldr)).Exec(context.Background(), NewCommand(CmdFind, WithArgs([]string{"git"})))
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	if len(tldr.finds) != 0 {
+		t.Errorf("tldr looked up %v, want the local cheat-sheet only", tldr.finds)
+	}
+
+	if want := []string{"# git\n\n- Status:\n\n`git status`\n"}; !reflect.DeepEqual(tldr.rendered, want) {
+		t.Errorf("rendered %q, want %q", tldr.rendered, want)
+	}
+}
+
+func TestExecFindTldr(t *testing.T) {
+	cfg := testConfig(t)
+	tldr := &fakeTldr{pages: map[string]string{"git-commit": "# git commit"}}
+
+	err := NewExecutor(cfg, WithTldr(tldr)).Exec(context.Background(), NewCommand(CmdFind, WithArgs([]string{"git", "commit"})))
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	if want := [][]string{{"git", "commit"}}; !reflect.DeepEqual(tldr.finds, want) {
+		t.Errorf("tldr looked up %v, want %v", tldr.finds, want)
+	}
+}
+
+func TestExecFindSourceLocalNotFound(t *testing.T) {
+	cfg := testConfig(t)
+	tldr := &fakeTldr{pages: map[string]string{"tar": "# tar"}}
+
+	cmd := NewCommand(CmdFind, WithArgs([]string{"tar"}), WithFlag(SourceFlag, SourceLocal))
+	err := NewExecutor(cfg, WithTldr(tldr)).Exec(context.Background(), cmd)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Exec = %v, want %v", err, ErrNotFound)
+	}
+
+	if len(tldr.finds) != 0 {
+		t.Errorf("tldr looked up %v, want nothing", tldr.finds)
+	}
+}
+
+func TestExecRenderSubstitutes(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Substitutions = map[string]string{"$PROJECT": "cs"}
+	writeSheet(t, cfg, "proj", "# proj\n\n<!-- private -->\n- Build:\n\n`go build -o $PROJECT`\n")
+	tldr := &fakeTldr{}
+
+	err := NewExecutor(cfg, WithTldr(tldr)).Exec(context.Background(), NewCommand(CmdFind, WithArgs([]string{"proj"})))
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	if len(tldr.rendered) != 1 {
+		t.Fatalf("rendered %d cheat-sheets, want 1", len(tldr.rendered))
+	}
+
+	got := tldr.rendered[0]
+	if !strings.Contains(got, "`go build -o cs`") {
+		t.Errorf("rendered %q, want $PROJECT substituted", got)
+	}
+	if strings.Contains(got, "private") {
+		t.Errorf("rendered %q, want notes left out", got)
+	}
+}
+
+func TestExecUpdate(t *testing.T) {
+	cfg := testConfig(t)
+	page := filepath.Join(cfg.TldrCachePath, "common", "tar.md")
+	tldr := &fakeTldr{onUpdate: func() error {
+		return os.WriteFile(page, []byte("# tar\n"), 0644)
+	}}
+
+	for _, cmd := range []*Command{
+		NewCommand(CmdUpdate),
+		NewCommand(CmdUpdate, WithFlag(PageFlag, "common")),
+	} {
+		if err := NewExecutor(cfg, WithTldr(tldr)).Exec(context.Background(), cmd); err != nil {
+			t.Fatalf("Exec(%v) failed: %v", cmd.Flags, err)
+		}
+	}
+
+	if tldr.updates != 2 {
+		t.Errorf("updated %d times, want 2", tldr.updates)
+	}
+
+	if _, err := os.Stat(filepath.Join(cfg.DataDir, updateLockFilename)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("update lock left behind: %v", err)
+	}
+}
+
+func TestExecFindOnlyRangeChecked(t *testing.T) {
+	cfg := testConfig(t)
+	writeSheet(t, cfg, "git", "# git\n\n- Status:\n\n`git status`\n")
+
+	for _, only := range []string{"0", "-1", "2"} {
+		tldr := &fakeTldr{}
+		cmd := NewCommand(CmdFind, WithArgs([]string{"git"}), WithFlag(OnlyFlag, only))
+		if err := NewExecutor(cfg, WithTldr(tldr)).Exec(context.Background(), cmd); err == nil {
+			t.Errorf("--only %v succeeded, want an error", only)
+		}
+
+		if len(tldr.rendered) != 0 {
+			t.Errorf("--only %v rendered %q, want nothing", only, tldr.rendered)
+		}
+	}
+}
+
+func TestExecEditCopiesFromCache(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.CheatSheetEditor = "true"
+	cfg.FileMode = "0600"
+	page := "# tar\n\n> Archiving utility.\n\n- Extract:\n\n`tar xf {{file}}`\n"
+	tldr := cachedTldr(t, cfg, map[string]string{"tar": page})
+
+	if path, err := tldr.FindFileInCache("tar.md"); err != nil || path == "" {
+		t.Fatalf("FindFileInCache = %q, %v, want the cached page", path, err)
+	}
+
+	err := NewExecutor(cfg, WithTldr(tldr)).Exec(context.Background(), NewCommand(CmdEdit, WithArgs([]string{"tar"})))
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	path := filepath.Join(cfg.CheatSheetsDir, "tar.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("no copy of the cached page: %v", err)
+	}
+	if got := string(data); got != page {
+		t.Errorf("copied %q, want %q", got, page)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0600 {
+		t.Errorf("copy has mode %v, want %v", got, os.FileMode(0600))
+	}
+}
+
+func TestExecUpdatePage(t *testing.T) {
+	cfg := testConfig(t)
+	tldr := &fakeTldr{canUpdatePage: true}
+
+	cmd := NewCommand(CmdUpdate, WithFlag(PageFlag, "linux"))
+	captureStdout(t, func() error {
+		return NewExecutor(cfg, WithTldr(tldr)).Exec(context.Background(), cmd)
+	})
+
+	if want := []string{"linux"}; !reflect.DeepEqual(tldr.pageUpdates, want) || tldr.updates != 0 {
+		t.Errorf("updated %v alone and all %d times, want %v alone", tldr.pageUpdates, tldr.updates, want)
+	}
+}
+
+func TestCanUpdatePage(t *testing.T) {
+	tests := []struct {
+		client string
+		help   string
+		want   bool
+	}{
+		{"platform option", "  -u, --update  Update the cache\n  -p, --platform PLATFORM  Override the platform\n", true},
+		{"platform listed first", "--platform=PLATFORM\n--update\n", true},
+		{"no platform option", "  -u, --update  Update the cache\n  -l, --list  List pages\n", false},
+		{"no update option", "  -p, --platform PLATFORM\n", false},
+		{"other option", "  --update\n  --platforms  List platforms\n", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanUpdatePage(tt.help); got != tt.want {
+			t.Errorf("CanUpdatePage of %v = %v, want %v", tt.client, got, tt.want)
+		}
+	}
+}
+
+func TestApplyTopicDefaultsLang(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Lang = "zh"
+	cfg.TopicDefaults = map[string]TopicDefaults{"curl": {Lang: "en"}}
+	e := NewExecutor(cfg, WithTldr(&fakeTldr{}))
+
+	tests := []struct {
+		cmd  *Command
+		want string
+	}{
+		{NewCommand(CmdFind, WithArgs([]string{"tar"})), "zh"},
+		{NewCommand(CmdFind, WithArgs([]string{"tar"}), WithFlag(LangFlag, "fr")), "fr"},
+		{NewCommand(CmdFind, WithArgs([]string{"curl"})), "en"},
+	}
+
+	for _, tt := range tests {
+		e.applyTopicDefaults(tt.cmd)
+		if got := tt.cmd.Flags[LangFlag]; got != tt.want {
+			t.Errorf("lang of %v = %q, want %q", tt.cmd.Args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Explain prints step by step how Find resolves the topic of the command,
+// without rendering it.
+func (e *Executor) Explain(cmd *Command) error {
+	step := 0
+	say := func(format string, args ...any) {
+		step++
+		fmt.Printf("%d. "+format+"\n", append([]any{step}, args...)...)
+	}
+
+	if len(cmd.Args) == 1 && IsURL(cmd.Args[0]) {
+		say("'%v' is a url: download it, or use its cached copy when offline or unreachable", cmd.Args[0])
+		say("would render it with the %v renderer", e.cfg.Renderer)
+		return nil
+	}
+
+	topic := cmd.Topic()
+	if joined := strings.Join(cmd.Args, " "); joined != topic {
+		say("normalized '%v' to topic '%v'", joined, topic)
+	}
+
+	for _, ext := range e.cfg.Extensions() {
+		for _, filename := range []string{topic + "." + ext, topic + "." + ext + EncryptedExt} {
+			path, err := e.explainLookup(say, "local", e.cfg.CheatSheetsDir, filename)
+			if err != nil || path == "" {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			if IsEncrypted(path) {
+				say("would decrypt it with the passphrase")
+			}
+
+			if len(e.cfg.Substitutions) > 0 && !cmd.HasFlag(NoSubstFlag) {
+				say("would apply %d substitutions", len(e.cfg.Substitutions))
+			}
+			say("would render it with the %v renderer", e.cfg.Renderer)
+			return nil
+		}
+	}
+
+	filename := topic + "." + DefaultExtension
+	if lang := cmd.Flags[LangFlag]; lang != "" && lang != "en" {
+		found, err := e.explainCache(say, strings.TrimSuffix(e.cfg.TldrCachePath, ".en")+"."+lang, filename)
+		if err != nil || found {
+			return err
+		}
+
+		if cmd.HasFlag(StrictLangFlag) {
+			say("not found in language '%v', strictly", lang)
+			return nil
+		}
+		say("no page in language '%v', fall back to english", lang)
+	}
+
+	found, err := e.explainCache(say, e.cfg.TldrCachePath, filename)
+	if err != nil || found {
+		return err
+	}
+
+	say("would ask '%v' anyway, then suggest similar topics if it doesn't find it either", e.cfg.TldrPath)
+	if e.cfg.FallbackCommand == "" {
+		say("no fallback_command configured")
+	} else if cmd.HasFlag(OfflineFlag) {
+		say("offline, so wouldn't run the fallback_command")
+	} else {
+		say("would run the fallback_command '%v'", e.cfg.FallbackCommand)
+	}
+	return nil
+}
+
+// explainCache narrates the lookup of the file in the page directories of
+// the tldr cache, reporting whether it was found.
+func (e *Executor) explainCache(say func(string, ...any), cachePath, filename string) (bool, error) {
+	for _, page := range e.cfg.TldrPages {
+		path, err := e.explainLookup(say, "tldr cache", filepath.Join(cachePath, page), filename)
+		if err != nil {
+			return false, err
+		}
+
+		if path == "" {
+			continue
+		}
+
+		if err := CheckReadable(path); err != nil {
+			say("skip it: %v", err)
+			continue
+		}
+
+		say("would render it with '%v'", e.cfg.TldrPath)
+		return true, nil
+	}
+	return false, nil
+}
+
+// explainLookup narrates the lookup of the file in the directory, returning
+// its path if found.
+func (e *Executor) explainLookup(say func(string, ...any), what, dir, filename string) (string, error) {
+	name, err := FindFileFold(dir, filename)
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		say("checked %v path '%v': miss", what, filepath.Join(dir, filename))
+		return "", nil
+	}
+
+	path := filepath.Join(dir, name)
+	say("checked %v path '%v': hit", what, path)
+	return path, nil
+}
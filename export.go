@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+const (
+	defaultSeparator = "----------------------------------------"
+	defaultHeader    = ""
+)
+
+// Delimiter writes the separator between cheat-sheets and the header before
+// each of them when several are output at once.
+type Delimiter struct {
+	separator string
+	header    *template.Template
+	count     int
+}
+
+// HeaderData is the data of the header template, e.g. `== {{.Topic}} ==`.
+// Source is `local` or the page set of a tldr page, e.g. `linux`.
+type HeaderData struct {
+	Topic  string
+	Path   string
+	Source string
+}
+
+func NewDelimiter(separator, header string) (*Delimiter, error) {
+	tmpl, err := template.New("header").Parse(header)
+	if err != nil {
+		return nil, fmt.Errorf("parse header template: %w", err)
+	}
+
+	return &Delimiter{
+		separator: separator,
+		header:    tmpl,
+	}, nil
+}
+
+// Next writes what comes before the next cheat-sheet.
+func (d *Delimiter) Next(w io.Writer, data HeaderData) error {
+	if d.count > 0 && d.separator != "" {
+		if _, err := fmt.Fprintln(w, d.separator); err != nil {
+			return err
+		}
+	}
+	d.count++
+
+	if d.header.Root == nil || len(d.header.Root.Nodes) == 0 {
+		return nil
+	}
+
+	if err := d.header.Execute(w, data); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func (e *Executor) delimiter(cmd *Command) (*Delimiter, error) {
+	separator := e.cfg.Separator
+	if val, ok := cmd.Flags[SeparatorFlag]; ok {
+		separator = val
+	}
+
+	header := e.cfg.Header
+	if val, ok := cmd.Flags[HeaderFlag]; ok {
+		header = val
+	}
+
+	return NewDelimiter(separator, header)
+}
+
+// Export writes the markdown of every local cheat-sheet to stdout, one
+// after another. Each file is streamed through a buffer, so memory stays
+// flat however large the collection.
+func (e *Executor) Export(cmd *Command) error {
+	sheets, missing, err := e.selectedSheets(cmd)
+	if err != nil {
+		return err
+	}
+
+	delim, err := e.delimiter(cmd)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	var result BulkResult
+	for _, sheet := range sheets {
+		if err := delim.Next(out, HeaderData{Topic: sheet.Topic, Path: sheet.Path}); err != nil {
+			return err
+		}
+
+		// A cheat-sheet failing to export doesn't stop the others.
+		result.Add(sheet.Topic, e.exportSheet(out, sheet.Path))
+	}
+
+	if err := out.Flush(); err != nil {
+		return err
+	}
+
+	for _, topic := range missing {
+		result.Add(topic, ErrNotFound)
+	}
+
+	if len(result.Failed) > 0 {
+		return result.Summary(os.Stderr, "exported")
+	}
+	return nil
+}
+
+// exportSheet writes the markdown of the cheat-sheet to w, streaming plain
+// files and decrypting encrypted ones, which are read whole.
+func (e *Executor) exportSheet(w io.Writer, path string) error {
+	if !IsEncrypted(path) {
+		return copyFileTo(w, path)
+	}
+
+	data, err := e.readSheet(path)
+	if err != nil {
+		return err
+	}
+
+	lw := &lastByteWriter{w: w}
+	if _, err := lw.Write(data); err != nil {
+		return err
+	}
+	return lw.endLine()
+}
+
+// copyFileTo copies the file to w, ending it with a newline if it lacks one
+// so the next delimiter starts on its own line.
+func copyFileTo(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	lw := &lastByteWriter{w: w}
+	if _, err := io.Copy(lw, f); err != nil {
+		return err
+	}
+	return lw.endLine()
+}
+
+type lastByteWriter struct {
+	w    io.Writer
+	last byte
+}
+
+// endLine writes a newline unless what was written ends with one.
+func (lw *lastByteWriter) endLine() error {
+	if lw.last == '\n' || lw.last == 0 {
+		return nil
+	}
+	_, err := io.WriteString(lw.w, "\n")
+	return err
+}
+
+func (lw *lastByteWriter) Write(p []byte) (int, error) {
+	n, err := lw.w.Write(p)
+	if n > 0 {
+		lw.last = p[n-1]
+	}
+	return n, err
+}
+
+// Formats of the files written by BatchRender.
+const (
+	BatchFormatMarkdown = "md"
+	BatchFormatHTML     = "html"
+	BatchFormatText     = "txt"
+)
+
+// BatchRender renders every local cheat-sheet into a file of its own in the
+// output directory, `<topic>.<format>`, creating the directory. Markdown is
+// the cheat-sheet as rendered, substituted and without notes, html is a page
+// like those of BuildSite and txt is the native rendering without colors.
+func (e *Executor) BatchRender(cmd *Command) error {
+	dir := cmd.Flags[OutFlag]
+	if dir == "" {
+		return errors.New("no output directory, usage: cs --batch-render --out <dir> [--format md|html|txt]")
+	}
+
+	format := BatchFormatMarkdown
+	if val, ok := cmd.Flags[FormatFlag]; ok {
+		format = val
+	}
+
+	switch format {
+	case BatchFormatMarkdown, BatchFormatHTML, BatchFormatText:
+	default:
+		return fmt.Errorf("unrecognized format: '%v', expected md, html or txt", format)
+	}
+
+	sheets, err := LocalCheatSheets(e.cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, e.cfg.DirPerm()); err != nil {
+		return err
+	}
+
+	var result BulkResult
+	for _, sheet := range sheets {
+		// A cheat-sheet failing to render doesn't stop the others.
+		result.Add(sheet.Topic, e.batchRenderSheet(cmd, sheet, filepath.Join(dir, sheet.Topic+"."+format), format))
+	}
+	return result.Summary(os.Stdout, "rendered")
+}
+
+// renderedContent returns the markdown of the cheat-sheet as rendered:
+// decrypted, without notes unless --show-notes and substituted unless
+// --no-subst.
+func (e *Executor) renderedContent(cmd *Command, path string) (string, error) {
+	data, err := e.readSheet(path)
+	if err != nil {
+		return "", err
+	}
+
+	content := string(data)
+	if !cmd.HasFlag(ShowNotesFlag) {
+		content = StripNotes(content)
+	}
+	if len(e.cfg.Substitutions) > 0 && !cmd.HasFlag(NoSubstFlag) {
+		content = Substitute(content, e.cfg.Substitutions)
+	}
+	return content, nil
+}
+
+func (e *Executor) batchRenderSheet(cmd *Command, sheet CheatSheet, path, format string) error {
+	content, err := e.renderedContent(cmd, sheet.Path)
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	switch format {
+	case BatchFormatHTML:
+		if out, err = RenderHTML(content, sheet.Topic); err != nil {
+			return err
+		}
+	case BatchFormatText:
+		var buf bytes.Buffer
+		if err := NewRenderer(Theme{}, &buf).Render(content); err != nil {
+			return err
+		}
+		out = buf.Bytes()
+	default:
+		out = []byte(content)
+	}
+	return WriteFileAtomic(path, out, e.cfg.FilePerm())
+}
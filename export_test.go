@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeLargeSheets writes n cheat-sheets of about size bytes each.
+func writeLargeSheets(tb testing.TB, cfg *Config, n, size int) {
+	tb.Helper()
+	example := "- List files:\n\n`ls -la {{path}}`\n\n"
+	body := "# big\n\n" + strings.Repeat(example, size/len(example))
+	for i := 0; i < n; i++ {
+		path := fmt.Sprintf("%v/big-%03d.%v", cfg.CheatSheetsDir, i, cfg.Extension)
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+func TestExportStreamsLargeSheets(t *testing.T) {
+	const n, size = 20, 1 << 20
+	cfg := testConfig(t)
+	writeLargeSheets(t, cfg, n, size)
+
+	out, err := os.Create(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	stdout := os.Stdout
+	os.Stdout = out
+	defer func() { os.Stdout = stdout }()
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	err = NewExecutor(cfg, WithTldr(&fakeTldr{})).Exec(context.Background(), NewCommand(CmdExport))
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	// Reading the sheets whole would allocate their total size.
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > n*size/4 {
+		t.Errorf("exporting %d sheets of %v allocated %v", n, FormatSize(size), FormatSize(int64(allocated)))
+	}
+}
+
+func TestExportOutput(t *testing.T) {
+	cfg := testConfig(t)
+	writeSheet(t, cfg, "git", "# git\n")
+	writeSheet(t, cfg, "tar", "# tar")
+
+	e := NewExecutor(cfg, WithTldr(&fakeTldr{}))
+	cmd := NewCommand(CmdExport, WithFlag(SeparatorFlag, "%%"), WithFlag(HeaderFlag, "== {{.Topic}} =="))
+	out := captureStdout(t, func() error { return e.Exec(context.Background(), cmd) })
+
+	want := "== git ==\n# git\n%%\n== tar ==\n# tar\n"
+	if out != want {
+		t.Errorf("exported %q, want %q", out, want)
+	}
+}
+
+func BenchmarkExport(b *testing.B) {
+	cfg := &Config{
+		CheatSheetsDir: b.TempDir(),
+		DataDir:        b.TempDir(),
+		Extension:      DefaultExtension,
+		Separator:      defaultSeparator,
+	}
+	writeLargeSheets(b, cfg, 200, 256<<10)
+
+	out, err := os.Create(os.DevNull)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer out.Close()
+
+	stdout := os.Stdout
+	os.Stdout = out
+	defer func() { os.Stdout = stdout }()
+
+	e := NewExecutor(cfg, WithTldr(&fakeTldr{}))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := e.Exec(context.Background(), NewCommand(CmdExport)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,3 +1,12 @@
 module github.com/yz-1209/cheat-sheet-tool
 
 go 1.19
+
+require (
+	github.com/BurntSushi/toml v1.3.2
+	golang.org/x/crypto v0.14.0
+	golang.org/x/term v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.13.0 // indirect
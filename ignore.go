@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const IgnoreFilename = ".csignore"
+
+// defaultIgnorePatterns exclude files of the tool itself from the
+// cheat-sheets directory.
+var defaultIgnorePatterns = []string{
+	".*",
+	"*~",
+	"*.bak",
+	"*.orig",
+	"template.*",
+}
+
+type ignoreRule struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+}
+
+// IgnoreRules excludes files of the cheat-sheets directory, written in the
+// gitignore syntax. The directory is flat, so patterns match file names.
+type IgnoreRules []ignoreRule
+
+// LoadIgnoreRules reads the `.csignore` file of the directory on top of the
+// default rules. A missing file only gives the default rules.
+func LoadIgnoreRules(dirname string) (IgnoreRules, error) {
+	rules := ParseIgnoreRules(defaultIgnorePatterns)
+
+	f, err := os.Open(filepath.Join(dirname, IgnoreFilename))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return rules, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return append(rules, ParseIgnoreRules(lines)...), nil
+}
+
+func ParseIgnoreRules(lines []string) IgnoreRules {
+	var rules IgnoreRules
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+
+		rule.pattern = strings.TrimPrefix(strings.TrimPrefix(line, "/"), "**/")
+		if rule.pattern != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// Ignored reports whether the file is excluded. Like gitignore, the last
+// matching rule wins so `!` rules can re-include files.
+func (r IgnoreRules) Ignored(name string, isDir bool) bool {
+	ignored := false
+	for _, rule := range r {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		if ok, _ := filepath.Match(rule.pattern, name); ok {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
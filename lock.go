@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	updateLockFilename = "update.lock"
+	// staleLockAge is how old a lock must be to be considered left over by
+	// a crashed process.
+	staleLockAge     = 10 * time.Minute
+	lockPollInterval = 200 * time.Millisecond
+)
+
+// FileLock is an advisory lock held by creating a file exclusively, with
+// the permissions of the config.
+type FileLock struct {
+	path     string
+	dirPerm  os.FileMode
+	filePerm os.FileMode
+}
+
+func NewFileLock(cfg *Config, path string) *FileLock {
+	return &FileLock{path: path, dirPerm: cfg.DirPerm(), filePerm: cfg.FilePerm()}
+}
+
+// TryLock takes the lock if it's free, reporting whether it was taken.
+func (l *FileLock) TryLock() (bool, error) {
+	if err := os.MkdirAll(filepath.Dir(l.path), l.dirPerm); err != nil {
+		return false, err
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, l.filePerm)
+	if err == nil {
+		fmt.Fprintf(f, "%d\n", os.Getpid())
+		return true, f.Close()
+	}
+
+	if !errors.Is(err, os.ErrExist) {
+		return false, err
+	}
+
+	if info, statErr := os.Stat(l.path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+		if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+		return l.TryLock()
+	}
+
+	return false, nil
+}
+
+// Wait blocks until the lock is released by its holder or is stale.
+func (l *FileLock) Wait() error {
+	for {
+		info, err := os.Stat(l.path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if time.Since(info.ModTime()) > staleLockAge {
+			return nil
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+func (l *FileLock) Unlock() error {
+	return os.Remove(l.path)
+}
@@ -1,34 +1,268 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 )
 
 const (
-	HelpFlag   = "h"
-	VerFlag    = "v"
-	EditFlag   = "e"
-	LogFlag    = "log"
-	UpdateFlag = "u"
+	HelpFlag             = "h"
+	VerFlag              = "v"
+	EditFlag             = "e"
+	LogFlag              = "log"
+	UpdateFlag           = "u"
+	BrowseFlag           = "browse"
+	ListFlag             = "l"
+	LongFlag             = "long"
+	LongListFlag         = "ll"
+	SourceFlag           = "source"
+	LangFlag             = "lang"
+	StrictLangFlag       = "strict-lang"
+	PrintConfigFlag      = "print-config"
+	ViewInEditorFlag     = "view-in-editor"
+	StatsFlag            = "stats"
+	JSONFlag             = "json"
+	FormatFlag           = "format"
+	PageFlag             = "page"
+	MergeFlag            = "merge"
+	EncryptFlag          = "encrypt"
+	RenameAllFlag        = "rename-all"
+	PreserveMtimeFlag    = "preserve-mtime"
+	AdoptFlag            = "adopt"
+	ImportURLFlag        = "import-url"
+	DedupeFlag           = "dedupe"
+	DeleteSourceFlag     = "delete-source"
+	PagesFlag            = "pages"
+	CacheDirFlag         = "cache-dir"
+	TldrArgsFlag         = "tldr-args"
+	ThemeFlag            = "theme"
+	OfflineFlag          = "offline"
+	DedupeCacheFlag      = "dedupe-cache"
+	DiffCacheFlag        = "diff-cache"
+	HardlinkFlag         = "hardlink"
+	PruneBackupsFlag     = "prune-backups"
+	KeepFlag             = "keep"
+	OlderThanFlag        = "older-than"
+	ShadowsFlag          = "shadows"
+	TopicsFlag           = "topics"
+	JSONStreamFlag       = "json-stream"
+	PreviewFlag          = "preview"
+	PeekFlag             = "peek"
+	SectionListFlag      = "section-list"
+	NoSubstFlag          = "no-subst"
+	ShowNotesFlag        = "show-notes"
+	UsageFlag            = "usage"
+	ResetFlag            = "reset"
+	MultiFlag            = "multi"
+	TopicsFileFlag       = "topics-file"
+	ExportFlag           = "export"
+	SeparatorFlag        = "separator"
+	HeaderFlag           = "header"
+	PrintExamplesFlag    = "print-examples"
+	ClipboardFlag        = "clipboard"
+	ExampleFormatFlag    = "example-format"
+	OpenURLFlag          = "open-url"
+	PrintURLFlag         = "print-url"
+	FromFlag             = "from"
+	ForceFlag            = "force"
+	YesFlag              = "yes"
+	CheckFlag            = "check"
+	VersionCheckFlag     = "version-check"
+	CheckUpdateFlag      = "check-update"
+	GotoFlag             = "goto"
+	BuildSiteFlag        = "build-site"
+	BatchRenderFlag      = "batch-render"
+	OutFlag              = "out"
+	WatchUpdateFlag      = "watch-update"
+	WatchDirFlag         = "watch-dir"
+	RandomFlag           = "random"
+	PickFlag             = "pick"
+	AllFlag              = "all"
+	TagFlag              = "tag"
+	ConfigFlag           = "config"
+	EditConfigFlag       = "edit-config"
+	EditTemplateFlag     = "edit-template"
+	TemplateFlag         = "template"
+	StrictEditorFlag     = "strict-editor"
+	SetupFlag            = "setup"
+	NoSetupFlag          = "no-setup"
+	ProfileFlag          = "profile"
+	DirFlag              = "dir"
+	ListProfilesFlag     = "list-profiles"
+	SearchFlag           = "search"
+	ResolveOnlyFlag      = "resolve-only"
+	ExplainFlag          = "explain"
+	NoRenderFlag         = "no-render"
+	RawFlag              = "raw"
+	StripFrontMatterFlag = "strip-front-matter"
+	NumberedFlag         = "numbered"
+	OnlyFlag             = "only"
+	HeadFlag             = "head"
+	TailFlag             = "tail"
+	GrepCacheFlag        = "grep-cache"
+	RegexFlag            = "regex"
+	LimitFlag            = "limit"
+	MinMatchFlag         = "min-match"
+	ReverseFlag          = "reverse"
+	ISOFlag              = "iso"
+	StripANSIFlag        = "strip-ansi"
+	ColorFlag            = "color"
+	PlainFlag            = "plain"
 )
 
-func main() {
+// NewFlagSet returns the flags of the command line.
+func NewFlagSet() *flag.FlagSet {
 	fs := flag.NewFlagSet("cheat-sheet flag set", flag.ContinueOnError)
 
 	fs.Bool(VerFlag, false, "print version")
 	fs.Bool(HelpFlag, false, "print usage")
 	fs.Bool(LogFlag, false, "print log")
 	fs.Bool(UpdateFlag, false, "update tldr cache")
+	fs.String(PageFlag, "", "tldr page set, e.g. linux, of -u, updating only it if the tldr client can, or of the page copied by -e and -adopt")
+	fs.String(DiffCacheFlag, "", "print the diff of the tldr pages of cheat-sheet name in two page directories given as arguments")
+	fs.Bool(DedupeCacheFlag, false, "report identical files across tldr cache pages")
+	fs.Bool(HardlinkFlag, false, "replace duplicates found by -dedupe-cache with hardlinks")
+	fs.Bool(PruneBackupsFlag, false, "delete backup files of cheat-sheets, like *.bak, but the most recent ones")
+	fs.String(KeepFlag, "", "number of most recent backups of each cheat-sheet kept by -prune-backups, 3 by default")
+	fs.String(OlderThanFlag, "", "only prune backups older than this, e.g. 30d or 12h")
+	fs.String(WatchUpdateFlag, "", "update tldr cache every interval, e.g. 6h, until interrupted")
+	fs.String(WatchDirFlag, "", "check the cheat-sheets directory every interval, e.g. 2s, keeping the topic index current until interrupted")
 	fs.String(EditFlag, "", "edit cheat-sheet name")
+	fs.String(ExplainFlag, "", "print step by step how cheat-sheet name is looked up, without rendering it")
+	fs.String(ResolveOnlyFlag, "", "print only the path of cheat-sheet name, e.g. for another markdown viewer")
+	fs.Bool(NumberedFlag, false, "number the examples of the cheat-sheet")
+	fs.String(OnlyFlag, "", "print only the example of this number of the cheat-sheet")
+	fs.String(HeadFlag, "", "print only the first lines of the rendered cheat-sheet, this many")
+	fs.String(TailFlag, "", "print only the last lines of the rendered cheat-sheet, this many")
+	fs.String(NoRenderFlag, "", "print the markdown of cheat-sheet name without rendering it, stripping front-matter per config")
+	fs.String(RawFlag, "", "print the markdown of cheat-sheet name as is")
+	fs.Bool(StripFrontMatterFlag, false, "strip the front-matter of -raw and -resolve-only cheat-sheets")
+	fs.Bool(TopicsFlag, false, "print the names of all topics, local and of the tldr cache, one per line for completion scripts")
+	fs.Bool(ShadowsFlag, false, "list personal cheat-sheets shadowing a tldr page")
+	fs.Bool(JSONStreamFlag, false, "print a json object per line for every topic: name, source, path and description")
+	fs.String(PreviewFlag, "", "render only cheat-sheet name, e.g. for the preview pane of fzf")
+	fs.String(PeekFlag, "", "print only the title and description of cheat-sheet name")
+	fs.String(SectionListFlag, "", "print the headings of cheat-sheet name by level, e.g. for -goto")
+	fs.String(PrintExamplesFlag, "", "print only the example commands of cheat-sheet name, one per line")
+	fs.String(ClipboardFlag, "", "copy an example command of cheat-sheet name to the clipboard")
+	fs.String(ExampleFormatFlag, "", "format of examples of -print-examples and -clipboard: command, commented, numbered or a template of {{.Index}}, {{.Description}} and {{.Command}}")
+	fs.String(OpenURLFlag, "", "open the upstream source of the tldr page of cheat-sheet name in the browser")
+	fs.Bool(PrintURLFlag, false, "print the url of -open-url instead of opening it")
+	fs.String(ViewInEditorFlag, "", "open cheat-sheet name read-only in the editor")
+	fs.String(FromFlag, "", "seed the cheat-sheet edited with -e from this one")
+	fs.String(GotoFlag, "", "open the cheat-sheet edited with -e at the first heading matching this")
+	fs.Bool(ForceFlag, false, "overwrite existing cheat-sheets")
+	fs.Bool(YesFlag, false, "don't prompt to confirm destructive operations, overriding confirm")
+	fs.String(AdoptFlag, "", "copy the tldr page of cheat-sheet name into the cheat-sheets directory, without editing it")
+	fs.String(ImportURLFlag, "", "download the markdown of this url into the cheat-sheets directory, named after the next argument or the url")
+	fs.String(EncryptFlag, "", "encrypt cheat-sheet name with a passphrase, replacing the plain file")
+	fs.String(RenameAllFlag, "", "rename cheat-sheets as listed by this file of 'old -> new' lines, all or none")
+	fs.Bool(PreserveMtimeFlag, true, "keep the modification time of tldr pages copied by -adopt and -e")
+	fs.String(MergeFlag, "", "merge examples of cheat-sheet name into the one given as argument")
+	fs.Bool(DedupeFlag, false, "skip examples already present when merging")
+	fs.Bool(DeleteSourceFlag, false, "delete the source cheat-sheet after merging")
+	fs.Bool(PickFlag, false, "pick a topic interactively, typing to filter, then render it")
+	fs.Bool(RandomFlag, false, "render a random local cheat-sheet")
+	fs.Bool(AllFlag, false, "include pages of the tldr cache of -random, or render every variant of cheat-sheet name, local and of each page set")
+	fs.String(TagFlag, "", "only cheat-sheets with this tag in their front-matter")
+	fs.Bool(BrowseFlag, false, "page through local cheat-sheets one at a time")
+	fs.Bool(ListFlag, false, "list local cheat-sheets")
+	fs.Bool(LongFlag, false, "list with descriptions")
+	fs.Bool(LongListFlag, false, "list local cheat-sheets with descriptions")
+	fs.String(SourceFlag, "", "list or find cheat-sheets of this source: local, tldr or all, the default except for -l listing local ones")
+	fs.Bool(StatsFlag, false, "print statistics of local cheat-sheets")
+	fs.Bool(JSONFlag, false, "print output as json")
+	fs.String(FormatFlag, "", "output format, for -stats one of table, json, prom, for -batch-render one of md, html, txt")
+	fs.String(CacheDirFlag, "", "tldr cache directory holding the page directories, defaults to $"+CacheDirEnv+" or tldr_cache_path")
+	fs.String(PagesFlag, "", "comma-separated tldr page directories to look up, e.g. common,osx, overriding tldr_pages")
+	fs.String(TldrArgsFlag, "", "extra space-separated arguments of every tldr run, depending on the installed client")
+	fs.String(ThemeFlag, "", "color theme of the native renderer: default, mono, solarized")
+	fs.Bool(MultiFlag, false, "render the cheat-sheet of each argument in turn")
+	fs.String(SearchFlag, "", "print lines of local cheat-sheets matching this, case-insensitively")
+	fs.String(GrepCacheFlag, "", "print lines of tldr cache pages matching this, case-insensitively")
+	fs.Bool(RegexFlag, false, "match -search or -grep-cache as a regular expression")
+	fs.String(LimitFlag, "", "maximum number of results")
+	fs.String(MinMatchFlag, "", "similarity from 0 to 1 of topics suggested when cheat-sheet name is found nowhere")
+	fs.Bool(ReverseFlag, false, "reverse the order of -l, -usage, -search and -grep-cache output")
+	fs.Bool(ISOFlag, false, "print times of -usage and -version-check as ISO-8601 instead of relative to now")
+	fs.String(ColorFlag, ColorAuto, "color output: auto, always, never")
+	fs.Bool(StripANSIFlag, false, "copy stdin to stdout without ANSI escape sequences, e.g. colors")
+	fs.Bool(PlainFlag, false, "render cheat-sheet name as plain text for scripts: no color, no prompts, failing when not found")
+	fs.Bool(BatchRenderFlag, false, "render each local cheat-sheet into a file of its own in the -out directory, as -format md, html or txt")
+	fs.String(OutFlag, "", "output directory of -batch-render")
+	fs.String(BuildSiteFlag, "", "render all local cheat-sheets to html pages with an index in this directory, removing its other html pages")
+	fs.String(TopicsFileFlag, "", "browse, export or print with -multi the topics listed in this file, one per line")
+	fs.Bool(ExportFlag, false, "print the markdown of all local cheat-sheets")
+	fs.String(SeparatorFlag, "", "separator between cheat-sheets of -multi and -export")
+	fs.String(HeaderFlag, "", "header template before each cheat-sheet of -multi and -export, e.g. '== {{.Topic}} =='")
+	fs.Bool(NoSubstFlag, false, "don't apply substitutions when rendering local cheat-sheets")
+	fs.Bool(ShowNotesFlag, false, "render the notes of cheat-sheets too, <!-- --> comments and lines starting with //")
+	fs.Bool(OfflineFlag, false, "don't access the network, use cached copies only")
+	fs.Bool(UsageFlag, false, "print how often and when each topic was looked up")
+	fs.Bool(ResetFlag, false, "clear the usage log of -usage")
+	fs.Bool(CheckFlag, false, "check the tool is usable, printing OK or FAIL: reason")
+	fs.Bool(VersionCheckFlag, false, "report the age of the tldr cache, warning when stale")
+	fs.Bool(CheckUpdateFlag, false, "report whether a newer release of the tool exists, without installing it")
+	fs.Var(new(repeatedFlag), ConfigFlag, "config file, yaml or toml by extension, repeated to layer files, later ones overriding earlier keys")
+	fs.String(ProfileFlag, "", "use the config.<profile>.yaml config and a data directory of the profile, defaults to $"+ProfileEnv)
+	fs.String(DirFlag, "", "cheat-sheets directory, holding the config files too, defaults to $"+DirEnv+" or ~/.cheat-sheet")
+	fs.Bool(ListProfilesFlag, false, "list profiles having a config file")
+	fs.Bool(SetupFlag, false, "set up the editor and tldr pages in the config file, and populate the tldr cache")
+	fs.Bool(NoSetupFlag, false, "don't offer the setup on the first run without a config file")
+	fs.Bool(EditConfigFlag, false, "edit the config file, creating it from a template if needed")
+	fs.Bool(EditTemplateFlag, false, "edit the template of new cheat-sheets, or the one named as argument, creating it if needed")
+	fs.Bool(StrictEditorFlag, false, "fail -e, -edit-config and -edit-template when the editor exits non-zero, instead of warning")
+	fs.String(TemplateFlag, "", "template of the new cheat-sheet edited with -e, made with -edit-template")
+	fs.Bool(PrintConfigFlag, false, "print the effective config as yaml")
+	fs.String(LangFlag, "", "language of tldr pages, falls back to english when missing")
+	fs.Bool(StrictLangFlag, false, "don't fall back to english when the -lang page is missing")
+	return fs
+}
+
+// topicFlags take the name of a cheat-sheet as value.
+var topicFlags = []string{EditFlag, AdoptFlag, EncryptFlag, MergeFlag}
+
+// completeTopicFlag gives an empty value to a topic flag ending the args,
+// e.g. of a bare `cs -e`, so that it's refused as an empty name rather than
+// failing to parse.
+func completeTopicFlag(args []string) []string {
+	if len(args) == 0 {
+		return args
+	}
+
+	for _, arg := range args[:len(args)-1] {
+		if arg == "--" {
+			// A topic, e.g. of `cs -- -e`.
+			return args
+		}
+	}
+
+	last := strings.TrimPrefix(strings.TrimPrefix(args[len(args)-1], "-"), "-")
+	for _, name := range topicFlags {
+		if last == name && strings.HasPrefix(args[len(args)-1], "-") {
+			return append(args, "")
+		}
+	}
+	return args
+}
+
+func main() {
+	fs := NewFlagSet()
 
 	var err error
 	if len(os.Args) < 2 {
 		err = fs.Set(HelpFlag, "true")
 	} else {
-		err = fs.Parse(os.Args[1:])
+		err = fs.Parse(completeTopicFlag(os.Args[1:]))
 	}
 
 	if err != nil {
@@ -36,23 +270,148 @@ func main() {
 		return
 	}
 
-	if err := Run(fs); err != nil {
-		fmt.Printf("run command failed: %v\n", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go exitOnInterrupt(ctx)
+
+	if err := Run(ctx, fs); err != nil {
+		if ctx.Err() != nil {
+			os.Exit(interruptedExitCode)
+		}
+
+		var exitErr *ExitError
+		if !errors.As(err, &exitErr) {
+			fmt.Printf("run command failed: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(exitErr.Code)
 	}
 }
 
-func Run(fs *flag.FlagSet) error {
+// interruptedExitCode is the exit code of a shell command interrupted with
+// Ctrl-C.
+const interruptedExitCode = 130
+
+// interruptGrace is how long the command has to wind down once interrupted,
+// e.g. blocked reading a prompt, before the tool exits anyway.
+const interruptGrace = time.Second
+
+// exitOnInterrupt exits the tool once interrupted when the command doesn't
+// return within the grace period, as it would if it didn't catch the signal.
+// Terminals changed by the command are restored first.
+func exitOnInterrupt(ctx context.Context) {
+	<-ctx.Done()
+	time.Sleep(interruptGrace)
+	RestoreTerminals()
+	os.Exit(interruptedExitCode)
+}
+
+// ExitError makes the tool exit with the code, the command having already
+// reported the failure.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// repeatedFlag is a flag given any number of times, collecting its values
+// in order.
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *repeatedFlag) Set(val string) error {
+	*f = append(*f, val)
+	return nil
+}
+
+// offersSetup tells whether the command is one to offer the setup before,
+// unlike those about the config or the tool itself.
+func offersSetup(cmd *Command) bool {
+	switch cmd.Cmd {
+	case CmdHelp, CmdVersion, CmdCheckUpdate, CmdSetup, CmdEditConfig, CmdPrintConfig, CmdCheck, CmdTopics, CmdStripANSI:
+		return false
+	}
+	return true
+}
+
+// Run runs the command of the flags, until the context is done.
+func Run(ctx context.Context, fs *flag.FlagSet) error {
 	cmd := CreateCommand(fs)
 
 	if cmd.PrintLog() {
 		log.Printf("create a new command %+v\n", cmd)
 	}
 
-	cfg, err := DefaultConfig()
+	profile := fs.Lookup(ProfileFlag).Value.String()
+	if profile == "" {
+		profile = os.Getenv(ProfileEnv)
+	}
+
+	configPaths := *fs.Lookup(ConfigFlag).Value.(*repeatedFlag)
+	cfg, err := DefaultConfig(configPaths, profile, fs.Lookup(DirFlag).Value.String())
 	if err != nil {
-		return err
+		if !errors.Is(err, ErrInvalidConfig) || cmd.Cmd != CmdEditConfig {
+			return err
+		}
+		warnf("%v", err)
+	}
+
+	if cmd.PrintLog() {
+		log.Printf("profile '%v', config file '%v'\n", profile, cfg.Path)
+		log.Printf("tldr cache '%v' of %v layout\n", cfg.TldrCachePath, cfg.CacheLayout)
+		for _, key := range cfg.UnknownKeys {
+			log.Printf("unknown config key '%v'\n", key)
+		}
+	}
+
+	if theme := fs.Lookup(ThemeFlag).Value.String(); theme != "" {
+		cfg.Theme.Base = theme
+	}
+
+	if fs.Lookup(YesFlag).Value.String() == "true" {
+		cfg.Confirm = ConfirmNever
+	}
+
+	if tldrArgs := fs.Lookup(TldrArgsFlag).Value.String(); tldrArgs != "" {
+		cfg.TldrArgs = strings.Fields(tldrArgs)
+	}
+
+	if cacheDir := fs.Lookup(CacheDirFlag).Value.String(); cacheDir != "" {
+		cfg.TldrCachePath = cacheDir
+		cfg.CacheLayout = LayoutTldr
+	}
+
+	if fs.Lookup(CacheDirFlag).Value.String() != "" || os.Getenv(CacheDirEnv) != "" {
+		if !isDir(cfg.TldrCachePath) {
+			return fmt.Errorf("tldr cache directory '%v' doesn't exist, it should hold page directories like common and linux",
+				cfg.TldrCachePath)
+		}
+	}
+
+	if pages := fs.Lookup(PagesFlag).Value.String(); pages != "" {
+		cfg.TldrPages = nil
+		for _, page := range strings.Split(pages, ",") {
+			if page = strings.TrimSpace(page); page == "" {
+				continue
+			}
+
+			if info, err := os.Stat(filepath.Join(cfg.TldrCachePath, page)); err != nil || !info.IsDir() {
+				warnf("no '%v' pages in the tldr cache '%v'", page, cfg.TldrCachePath)
+			}
+			cfg.TldrPages = append(cfg.TldrPages, page)
+		}
 	}
 
 	executor := NewExecutor(cfg)
-	return executor.Exec(cmd)
+	if offersSetup(cmd) && !cmd.HasFlag(PlainFlag) && fs.Lookup(NoSetupFlag).Value.String() != "true" {
+		if err := executor.OfferSetup(); err != nil {
+			warnf("setup failed: %v", err)
+		}
+	}
+	return executor.Exec(ctx, cmd)
 }
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// ErrPickCanceled is returned when the picker is left without picking.
+var ErrPickCanceled = errors.New("pick canceled")
+
+const maxPickerHeight = 10
+
+// Pick lets the user pick one of the items on the terminal, typing to
+// filter them and moving with the arrow keys or Ctrl-P and Ctrl-N, starting
+// from the selected one. Enter picks, Esc cancels, and Ctrl-C interrupts
+// the tool as it would out of raw mode. The terminal is restored however
+// the picker is left, also when the context is done.
+func Pick(ctx context.Context, items []string, selected int) (string, error) {
+	tty, err := os.OpenFile(ttyPath, os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("no terminal to pick from: %w", err)
+	}
+	defer tty.Close()
+
+	// Not Fd, which would make reads blocking, past deadlines.
+	conn, err := tty.SyscallConn()
+	if err != nil {
+		return "", err
+	}
+
+	var fd int
+	conn.Control(func(ptr uintptr) {
+		fd = int(ptr)
+	})
+
+	raw, err := MakeRawTerminal(fd, tty)
+	if err != nil {
+		return "", err
+	}
+	defer raw.Restore()
+
+	// Wake up the read of the next key once interrupted.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			tty.SetReadDeadline(time.Now())
+		case <-done:
+		}
+	}()
+
+	height := maxPickerHeight
+	if _, rows, err := term.GetSize(fd); err == nil && rows > 1 && rows-1 < height {
+		height = rows - 1
+	}
+
+	p := newPicker(items, selected, height)
+	item, err := p.run(bufio.NewReader(tty), tty)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	return item, err
+}
+
+type picker struct {
+	items   []string
+	matches []int
+	cursor  int
+	filter  []rune
+	height  int
+	drawn   int
+}
+
+func newPicker(items []string, selected, height int) *picker {
+	p := &picker{items: items, height: height}
+	p.match(selected)
+	return p
+}
+
+// match filters the items case-insensitively, keeping the cursor on the
+// selected item if it still matches.
+func (p *picker) match(selected int) {
+	filter := strings.ToLower(string(p.filter))
+	p.matches = p.matches[:0]
+	p.cursor = 0
+	for i, item := range p.items {
+		if !strings.Contains(strings.ToLower(item), filter) {
+			continue
+		}
+
+		if i == selected {
+			p.cursor = len(p.matches)
+		}
+		p.matches = append(p.matches, i)
+	}
+}
+
+func (p *picker) selected() int {
+	if len(p.matches) == 0 {
+		return -1
+	}
+	return p.matches[p.cursor]
+}
+
+func (p *picker) run(in *bufio.Reader, out io.Writer) (string, error) {
+	defer p.clear(out)
+	for {
+		p.draw(out)
+		r, _, err := in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			if i := p.selected(); i >= 0 {
+				return p.items[i], nil
+			}
+		case 3: // Ctrl-C
+			return "", &ExitError{Code: interruptedExitCode}
+		case 27: // Esc, or the start of an arrow key sequence
+			if in.Buffered() == 0 {
+				return "", ErrPickCanceled
+			}
+
+			if next, _, _ := in.ReadRune(); next != '[' {
+				continue
+			}
+
+			switch key, _, _ := in.ReadRune(); key {
+			case 'A':
+				p.move(-1)
+			case 'B':
+				p.move(1)
+			}
+		case 16: // Ctrl-P
+			p.move(-1)
+		case 14: // Ctrl-N
+			p.move(1)
+		case 127, 8: // Backspace
+			if len(p.filter) > 0 {
+				p.filter = p.filter[:len(p.filter)-1]
+				p.match(p.selected())
+			}
+		default:
+			if r >= ' ' {
+				p.filter = append(p.filter, r)
+				p.match(p.selected())
+			}
+		}
+	}
+}
+
+func (p *picker) move(delta int) {
+	if n := len(p.matches); n > 0 {
+		p.cursor = (p.cursor + delta + n) % n
+	}
+}
+
+// draw redraws the window of matches around the cursor above the filter
+// prompt, over the previous drawing. Raw mode needs explicit carriage
+// returns.
+func (p *picker) draw(out io.Writer) {
+	var b strings.Builder
+	p.rewind(&b)
+
+	start := 0
+	if p.cursor >= p.height {
+		start = p.cursor - p.height + 1
+	}
+
+	p.drawn = 0
+	for i := start; i < len(p.matches) && i < start+p.height; i++ {
+		item := p.items[p.matches[i]]
+		if i == p.cursor {
+			b.WriteString("\x1b[7m> " + item + "\x1b[0m\r\n")
+		} else {
+			b.WriteString("  " + item + "\r\n")
+		}
+		p.drawn++
+	}
+
+	fmt.Fprintf(&b, "[%d/%d] pick: %s", len(p.matches), len(p.items), string(p.filter))
+	io.WriteString(out, b.String())
+}
+
+func (p *picker) clear(out io.Writer) {
+	var b strings.Builder
+	p.rewind(&b)
+	io.WriteString(out, b.String())
+	p.drawn = 0
+}
+
+// rewind moves back to the first line drawn and erases down from there.
+func (p *picker) rewind(b *strings.Builder) {
+	if p.drawn > 0 {
+		fmt.Fprintf(b, "\r\x1b[%dA", p.drawn)
+	}
+	b.WriteString("\r\x1b[J")
+}
+
+// PickTopic lets the user pick a topic among the local cheat-sheets and the
+// pages of the tldr cache, starting from the most recently used one if
+// configured, then finds it.
+func (e *Executor) PickTopic(cmd *Command) error {
+	topics, err := e.topics()
+	if err != nil {
+		return err
+	}
+
+	if len(topics) == 0 {
+		fmt.Println("no cheat-sheets to pick from")
+		return nil
+	}
+
+	selected := 0
+	if e.cfg.PickMRU {
+		usages, err := LoadUsage(e.cfg)
+		if err != nil {
+			return err
+		}
+
+		if mru := MostRecentTopic(usages); mru != "" {
+			for i, topic := range topics {
+				if topic == mru {
+					selected = i
+					break
+				}
+			}
+		}
+	}
+
+	topic, err := Pick(e.ctx, topics, selected)
+	if errors.Is(err, ErrPickCanceled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	cmd.Args = []string{topic}
+	if err := e.Find(cmd); err != nil {
+		return err
+	}
+	e.recordUsage(cmd)
+	return nil
+}
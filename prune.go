@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DefaultKeepBackups = 3
+
+// backupSuffix matches the suffixes of backup files of a cheat-sheet, e.g.
+// `git.md.bak`, `git.md~` or `git.md.20240102150405.bak`.
+var backupSuffix = regexp.MustCompile(`(\.[0-9T:-]+)?(\.bak|~)$`)
+
+// Backup is a backup file of the cheat-sheet Of.
+type Backup struct {
+	Of      string
+	Path    string
+	ModTime time.Time
+}
+
+// FindBackups groups the backup files of the directory by the cheat-sheet
+// file they back up, most recent first.
+func FindBackups(dirname string) (map[string][]Backup, error) {
+	entries, err := os.ReadDir(dirname)
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make(map[string][]Backup)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !backupSuffix.MatchString(name) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		of := backupSuffix.ReplaceAllString(name, "")
+		backups[of] = append(backups[of], Backup{Of: of, Path: filepath.Join(dirname, name), ModTime: info.ModTime()})
+	}
+
+	for _, group := range backups {
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].ModTime.After(group[j].ModTime)
+		})
+	}
+	return backups, nil
+}
+
+// ParseAge parses an age as days, e.g. `30d`, or as a duration, e.g. `12h`.
+func ParseAge(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age '%v'", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age '%v', expect e.g. 30d or 12h", s)
+	}
+	return d, nil
+}
+
+// PruneBackups deletes the backup files of the cheat-sheets directory but
+// the --keep most recent of each cheat-sheet, and only those older than
+// --older-than if given.
+func (e *Executor) PruneBackups(cmd *Command) error {
+	keep, err := cmd.IntFlag(KeepFlag, DefaultKeepBackups)
+	if err != nil {
+		return err
+	}
+
+	if keep < 0 {
+		return fmt.Errorf("invalid -%v %d, expect 0 or more", KeepFlag, keep)
+	}
+
+	var olderThan time.Duration
+	if val := cmd.Flags[OlderThanFlag]; val != "" {
+		if olderThan, err = ParseAge(val); err != nil {
+			return err
+		}
+	}
+
+	backups, err := FindBackups(e.cfg.CheatSheetsDir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var pruned []Backup
+	for _, group := range backups {
+		for i, backup := range group {
+			if i >= keep && now.Sub(backup.ModTime) >= olderThan {
+				pruned = append(pruned, backup)
+			}
+		}
+	}
+
+	if len(pruned) == 0 {
+		fmt.Println("no backups to prune")
+		return nil
+	}
+
+	sort.Slice(pruned, func(i, j int) bool {
+		return pruned[i].Path < pruned[j].Path
+	})
+
+	if !e.confirm(true, "delete %d backups?", len(pruned)) {
+		fmt.Println("kept the backups")
+		return nil
+	}
+
+	var result BulkResult
+	for _, backup := range pruned {
+		err := os.Remove(backup.Path)
+		if err == nil {
+			fmt.Printf("deleted '%v'\n", backup.Path)
+		}
+		result.Add(filepath.Base(backup.Path), err)
+	}
+
+	if len(result.Failed) > 0 {
+		return result.Summary(os.Stderr, "deleted")
+	}
+	return nil
+}
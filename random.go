@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Random renders a randomly chosen local cheat-sheet, or any page of the
+// tldr cache with --all.
+func (e *Executor) Random(cmd *Command) error {
+	sheets, err := LocalCheatSheets(e.cfg)
+	if err != nil {
+		return err
+	}
+
+	if cmd.HasFlag(AllFlag) {
+		cached, err := CachedCheatSheets(e.cfg.TldrCachePath, e.cfg.TldrPages)
+		if err != nil {
+			return err
+		}
+		sheets = append(sheets, cached...)
+	}
+
+	if tag := cmd.Flags[TagFlag]; tag != "" {
+		var tagged []CheatSheet
+		for _, sheet := range sheets {
+			ok, err := HasTag(sheet.Path, tag)
+			if err != nil {
+				return err
+			}
+
+			if ok {
+				tagged = append(tagged, sheet)
+			}
+		}
+		sheets = tagged
+	}
+
+	if len(sheets) == 0 {
+		fmt.Println("no cheat-sheets to pick from")
+		return nil
+	}
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	sheet := sheets[rnd.Intn(len(sheets))]
+	if sheet.Local {
+		return e.renderLocal(cmd, sheet.Path)
+	}
+	return e.render(sheet.Path)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ReleasesURL is the GitHub API of the latest release of the tool.
+	ReleasesURL = "https://api.github.com/repos/yz-1209/cheat-sheet-tool/releases/latest"
+	// releaseCacheTTL is how long the latest release checked is trusted.
+	releaseCacheTTL      = 24 * time.Hour
+	releaseCacheFilename = "latest-release.json"
+)
+
+var errCorruptRelease = errors.New("corrupt release cache")
+
+// Release is the latest release of the tool, as cached by CheckUpdate.
+type Release struct {
+	TagName   string    `json:"tag_name"`
+	HTMLURL   string    `json:"html_url"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// CompareVersions compares dotted versions like `v1.2.3` number by number,
+// ignoring a leading `v` and a `-rc.1` like suffix, returning -1, 0 or 1.
+// Missing numbers count as 0.
+func CompareVersions(a, b string) int {
+	split := func(v string) []string {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+		v, _, _ = strings.Cut(v, "-")
+		return strings.Split(v, ".")
+	}
+
+	as, bs := split(a), split(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// CheckUpdate reports whether a release of the tool newer than this one
+// exists, with its url. The latest release is asked to GitHub at most once a
+// day, and only the cached answer is used when offline. Nothing is
+// installed.
+func (e *Executor) CheckUpdate(cmd *Command) error {
+	release, err := e.latestRelease(cmd)
+	if err != nil {
+		return err
+	}
+
+	current := strings.TrimSpace(version)
+	if CompareVersions(release.TagName, current) <= 0 {
+		fmt.Printf("cheat-sheet %v is the latest version\n", current)
+		return nil
+	}
+
+	fmt.Printf("cheat-sheet %v is available, you have %v: %v\n",
+		strings.TrimPrefix(release.TagName, "v"), current, release.HTMLURL)
+	return nil
+}
+
+func (e *Executor) latestRelease(cmd *Command) (*Release, error) {
+	path := filepath.Join(e.cfg.DataDir, releaseCacheFilename)
+	cached, err := readRelease(path)
+	if errors.Is(err, errCorruptRelease) {
+		// Left partial by a crash or a sync conflict, asked again below.
+		if cmd.PrintLog() {
+			log.Printf("discard release cache: %v\n", err)
+		}
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if cmd.HasFlag(OfflineFlag) {
+		if cached == nil {
+			return nil, fmt.Errorf("%w: no release checked yet", ErrOffline)
+		}
+		return cached, nil
+	}
+
+	if cached != nil && time.Since(cached.CheckedAt) < releaseCacheTTL {
+		if cmd.PrintLog() {
+			log.Printf("use release checked at %v\n", cached.CheckedAt)
+		}
+		return cached, nil
+	}
+
+	release, err := e.fetchRelease()
+	if err != nil {
+		if cached != nil {
+			warnf("check for updates failed, using the last answer: %v", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	data, err := json.Marshal(release)
+	if err != nil {
+		return nil, err
+	}
+
+	return release, e.cfg.WriteState(path, data)
+}
+
+func readRelease(path string) (*Release, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var release Release
+	if err := json.Unmarshal(data, &release); err != nil || release.TagName == "" {
+		return nil, fmt.Errorf("%w '%v'", errCorruptRelease, path)
+	}
+	return &release, nil
+}
+
+func (e *Executor) fetchRelease() (*Release, error) {
+	req, err := http.NewRequestWithContext(e.ctx, http.MethodGet, ReleasesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{Timeout: remoteTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch '%v': %v", ReleasesURL, resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(io.LimitReader(resp.Body, remoteMaxSize)).Decode(&release); err != nil {
+		return nil, fmt.Errorf("fetch '%v': %w", ReleasesURL, err)
+	}
+
+	if release.TagName == "" {
+		return nil, fmt.Errorf("fetch '%v': no tag_name", ReleasesURL)
+	}
+	release.CheckedAt = time.Now()
+	return &release, nil
+}
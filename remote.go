@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	remoteTimeout = 10 * time.Second
+	// remoteMaxSize caps the size of a remote cheat-sheet.
+	remoteMaxSize = 1 << 20
+)
+
+var (
+	ErrOffline    = errors.New("offline")
+	ErrInvalidURL = errors.New("invalid url")
+)
+
+// IsURL reports whether the topic is a http(s) url of a remote cheat-sheet.
+func IsURL(topic string) bool {
+	return strings.HasPrefix(topic, "http://") || strings.HasPrefix(topic, "https://")
+}
+
+// FetchURL downloads a remote cheat-sheet, checking it's text no larger than
+// remoteMaxSize. It's cancelled when the context is done.
+func FetchURL(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: remoteTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch '%v': %v", url, resp.Status)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !strings.HasPrefix(mediaType, "text/") {
+			return nil, fmt.Errorf("fetch '%v': unexpected content type '%v'", url, contentType)
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, remoteMaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > remoteMaxSize {
+		return nil, fmt.Errorf("fetch '%v': larger than %d bytes", url, remoteMaxSize)
+	}
+
+	return data, nil
+}
+
+// URLCachePath returns where the remote cheat-sheet of the url is cached.
+func URLCachePath(cfg *Config, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(cfg.DataDir, "urls", hex.EncodeToString(sum[:8])+"."+DefaultExtension)
+}
+
+// FetchCachedURL downloads the remote cheat-sheet into the url cache and
+// returns its path there. When offline or the download fails, the cached
+// copy is used if there is one. The download is cancelled when the context
+// is done.
+func FetchCachedURL(ctx context.Context, cfg *Config, url string, offline bool) (string, error) {
+	path := URLCachePath(cfg, url)
+	if offline {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("%w: '%v' isn't cached", ErrOffline, url)
+		}
+		return path, nil
+	}
+
+	data, err := FetchURL(ctx, url)
+	if err != nil {
+		if ctx.Err() != nil {
+			// Interrupted, not failed.
+			return "", err
+		}
+
+		if _, statErr := os.Stat(path); statErr == nil {
+			warnf("%v, use cached copy", err)
+			return path, nil
+		}
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), cfg.DirPerm()); err != nil {
+		return "", err
+	}
+
+	if err := WriteFileAtomic(path, data, cfg.FilePerm()); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ImportURL downloads the remote cheat-sheet of the url into the
+// cheat-sheets directory, named after the second argument or else the last
+// element of the url path, e.g. `git` of `https://example.com/git.md`.
+func (e *Executor) ImportURL(cmd *Command) error {
+	if len(cmd.Args) == 0 || cmd.Args[0] == "" {
+		return fmt.Errorf("%w: empty, usage: cs --import-url <url> [name]", ErrInvalidURL)
+	}
+
+	rawURL := cmd.Args[0]
+	if u, err := url.Parse(rawURL); err != nil || !IsURL(rawURL) || u.Host == "" {
+		return fmt.Errorf("%w '%v', expect a http(s) url", ErrInvalidURL, rawURL)
+	}
+
+	if cmd.HasFlag(OfflineFlag) {
+		return fmt.Errorf("%w: can't import '%v'", ErrOffline, rawURL)
+	}
+
+	topic := NormalizeTopic(cmd.Args[1:]...)
+	if topic == "" {
+		topic = topicOfURL(rawURL)
+	}
+
+	if topic == "" {
+		return fmt.Errorf("%w: can't infer a name from '%v', usage: cs --import-url <url> <name>", ErrEmptyTopic, rawURL)
+	}
+	cmd.Args = []string{topic}
+
+	path, err := e.findLocalTopic(topic)
+	if err != nil {
+		return err
+	}
+
+	if path != "" && IsEncrypted(path) {
+		return fmt.Errorf("cheat-sheet '%v' is encrypted, import it under another name", topic)
+	}
+
+	data, err := FetchURL(e.ctx, rawURL)
+	if err != nil {
+		return err
+	}
+
+	if err := e.checkText(cmd, topic, data); err != nil {
+		return err
+	}
+
+	if err := e.checkSizeCap(cmd, topic, int64(len(data))); err != nil {
+		return err
+	}
+
+	if err := e.confirmOverwrite(cmd, path); err != nil {
+		return err
+	}
+
+	if path == "" {
+		path = filepath.Join(e.cfg.CheatSheetsDir, cmd.Filename(e.cfg.Extension))
+	}
+
+	if err := WriteFileAtomic(path, data, e.cfg.FilePerm()); err != nil {
+		return err
+	}
+
+	fmt.Printf("imported '%v' from '%v'\n", topic, rawURL)
+	return nil
+}
+
+// topicOfURL returns the topic of the last element of the url path, without
+// its markdown or text extension, or "" when there is none.
+func topicOfURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		return ""
+	}
+
+	for _, ext := range []string{".md", ".markdown", ".txt"} {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			name = name[:len(name)-len(ext)]
+			break
+		}
+	}
+	return NormalizeTopic(name)
+}
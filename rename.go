@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Rename is an `old -> new` pair of a rename mapping file.
+type Rename struct {
+	Old string
+	New string
+}
+
+// ParseRenames reads `old -> new` pairs, one per line. Blank lines and lines
+// starting with `#` are skipped.
+func ParseRenames(r io.Reader) ([]Rename, error) {
+	var renames []Rename
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		oldTopic, newTopic, ok := strings.Cut(line, "->")
+		oldTopic, newTopic = NormalizeTopic(oldTopic), NormalizeTopic(newTopic)
+		if !ok || oldTopic == "" || newTopic == "" {
+			return nil, fmt.Errorf("line %d: expected 'old -> new', got '%v'", n, line)
+		}
+		renames = append(renames, Rename{Old: oldTopic, New: newTopic})
+	}
+	return renames, scanner.Err()
+}
+
+// RenameAll renames local cheat-sheets as listed by the mapping file. Every
+// pair is checked before renaming any, and renames done are undone when one
+// fails, so that either all of them or none are applied.
+func (e *Executor) RenameAll(cmd *Command) error {
+	f, err := os.Open(cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	renames, err := ParseRenames(f)
+	if err != nil {
+		return fmt.Errorf("parse '%v': %w", cmd.Args[0], err)
+	}
+
+	var moves []move
+	var result BulkResult
+	seen := make(map[string]string)
+	for _, rename := range renames {
+		m, err := e.checkRename(rename, seen)
+		result.Add(rename.Old+" -> "+rename.New, err)
+		if err == nil {
+			moves = append(moves, m)
+		}
+	}
+
+	if len(result.Failed) > 0 {
+		fmt.Fprintln(os.Stderr, "nothing renamed")
+		return result.Summary(os.Stderr, "valid renames")
+	}
+
+	for i, m := range moves {
+		if err := os.Rename(m.src, m.dest); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				if undoErr := os.Rename(moves[j].dest, moves[j].src); undoErr != nil {
+					return fmt.Errorf("%w, and undoing renames failed: %v", err, undoErr)
+				}
+			}
+			return fmt.Errorf("nothing renamed: %w", err)
+		}
+
+		if cmd.PrintLog() {
+			log.Printf("renamed '%v' to '%v'\n", m.src, m.dest)
+		}
+	}
+
+	fmt.Printf("renamed %d cheat-sheets\n", len(moves))
+	return nil
+}
+
+type move struct {
+	src  string
+	dest string
+}
+
+// checkRename returns the move of the rename, checking its cheat-sheet exists
+// and that nothing else is or will be at its destination. seen maps the
+// destinations of the renames checked so far to their source.
+func (e *Executor) checkRename(rename Rename, seen map[string]string) (move, error) {
+	src, err := e.findLocalTopic(rename.Old)
+	if err != nil {
+		return move{}, err
+	}
+
+	if src == "" {
+		return move{}, fmt.Errorf("%w: '%v'", ErrNotFound, rename.Old)
+	}
+
+	dest, err := e.findLocalTopic(rename.New)
+	if err != nil {
+		return move{}, err
+	}
+
+	if dest != "" {
+		return move{}, fmt.Errorf("'%v' already exists", rename.New)
+	}
+
+	if other, ok := seen[rename.New]; ok {
+		return move{}, fmt.Errorf("'%v' is also renamed to '%v'", other, rename.New)
+	}
+	seen[rename.New] = rename.Old
+
+	return move{src: src, dest: filepath.Join(e.cfg.CheatSheetsDir, rename.New+sheetSuffix(src))}, nil
+}
+
+// sheetSuffix returns the suffix of the cheat-sheet file following its topic,
+// e.g. `.md`, or `.md.enc` for an encrypted one.
+func sheetSuffix(path string) string {
+	if IsEncrypted(path) {
+		return filepath.Ext(strings.TrimSuffix(path, EncryptedExt)) + EncryptedExt
+	}
+	return filepath.Ext(path)
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	RendererTldr   = "tldr"
+	RendererNative = "native"
+	// RendererBat pipes cheat-sheets through bat, falling back to the native
+	// renderer when it isn't installed.
+	RendererBat = "bat"
+)
+
+// Renderer renders tldr-markdown cheat-sheets to the terminal without
+// shelling out to a tldr client.
+type Renderer struct {
+	theme Theme
+	out   io.Writer
+}
+
+func NewRenderer(theme Theme, out io.Writer) *Renderer {
+	return &Renderer{
+		theme: theme,
+		out:   out,
+	}
+}
+
+func (r *Renderer) RenderFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return r.Render(string(data))
+}
+
+const (
+	FormatTldr     = "tldr"
+	FormatMarkdown = "md"
+)
+
+// DetectFormat returns the markdown flavor of a cheat-sheet: the
+// `format: tldr|md` front-matter key when set, else tldr when it has a `>`
+// description and a `-` example followed by a backticked command, else
+// generic markdown.
+func DetectFormat(content string) string {
+	meta, body := ParseFrontMatter(content)
+	switch format := strings.ToLower(meta["format"]); format {
+	case FormatTldr, FormatMarkdown:
+		return format
+	case "":
+	default:
+		warnf("unrecognized cheat-sheet format '%v', detecting it", format)
+	}
+
+	hasDescription, inExample := false, false
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, ">"):
+			hasDescription = true
+		case strings.HasPrefix(line, "- "):
+			inExample = true
+		case strings.HasPrefix(line, "`") && inExample && hasDescription:
+			return FormatTldr
+		default:
+			inExample = false
+		}
+	}
+	return FormatMarkdown
+}
+
+// Render renders the cheat-sheet after its front-matter, in its detected
+// markdown flavor.
+func (r *Renderer) Render(content string) error {
+	format := DetectFormat(content)
+	_, body := ParseFrontMatter(content)
+	if format == FormatMarkdown {
+		return r.renderMarkdown(body)
+	}
+	return r.renderTldr(body)
+}
+
+func (r *Renderer) renderTldr(content string) error {
+	var lines []string
+	blank := true
+	input := strings.Split(content, "\n")
+	for i := 0; i < len(input); i++ {
+		if n := tableLen(input[i:]); n > 0 {
+			lines = append(lines, r.renderTable(input[i:i+n])...)
+			blank = false
+			i += n - 1
+			continue
+		}
+
+		line := strings.TrimSpace(input[i])
+		if line == "" {
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+
+		blank = false
+		switch {
+		case strings.HasPrefix(line, "# "):
+			lines = append(lines, "  "+r.theme.Paint(ElemTitle, strings.TrimSpace(line[2:])))
+		case strings.HasPrefix(line, ">"):
+			lines = append(lines, "  "+r.theme.Paint(ElemDescription, strings.TrimSpace(line[1:])))
+		case strings.HasPrefix(line, "- "):
+			lines = append(lines, "  "+r.theme.Paint(ElemExample, line))
+		case strings.HasPrefix(line, "`") && strings.HasSuffix(line, "`") && len(line) > 1:
+			lines = append(lines, "    "+r.renderCode(line[1:len(line)-1]))
+		default:
+			lines = append(lines, "  "+line)
+		}
+	}
+
+	return r.write(lines)
+}
+
+// renderMarkdown renders generic markdown: headings, quotes, fenced code
+// blocks kept verbatim, tables and inline code.
+func (r *Renderer) renderMarkdown(content string) error {
+	var lines []string
+	blank, fenced := true, false
+	input := strings.Split(content, "\n")
+	for i := 0; i < len(input); i++ {
+		line := input[i]
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			fenced = !fenced
+			continue
+		}
+
+		if fenced {
+			lines = append(lines, "    "+r.theme.Paint(ElemCode, strings.TrimRight(line, " \t")))
+			blank = false
+			continue
+		}
+
+		if n := tableLen(input[i:]); n > 0 {
+			lines = append(lines, r.renderTable(input[i:i+n])...)
+			blank = false
+			i += n - 1
+			continue
+		}
+
+		if trimmed == "" {
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+
+		blank = false
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			lines = append(lines, "  "+r.theme.Paint(ElemTitle, strings.TrimSpace(strings.TrimLeft(trimmed, "#"))))
+		case strings.HasPrefix(trimmed, ">"):
+			lines = append(lines, "  "+r.theme.Paint(ElemDescription, strings.TrimSpace(trimmed[1:])))
+		default:
+			lines = append(lines, "  "+r.renderInlineCode(strings.TrimRight(line, " \t")))
+		}
+	}
+
+	return r.write(lines)
+}
+
+// renderInlineCode colors the backticked spans of a line.
+func (r *Renderer) renderInlineCode(line string) string {
+	parts := strings.Split(line, "`")
+	if !balancedBackticks(parts) {
+		// Unbalanced backticks, not code.
+		return line
+	}
+
+	var b strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 {
+			b.WriteString(r.renderCode(part))
+		} else {
+			b.WriteString(part)
+		}
+	}
+	return b.String()
+}
+
+func balancedBackticks(parts []string) bool {
+	return len(parts)%2 == 1
+}
+
+// visibleWidth returns the width of the line once its inline code is
+// rendered without the backticks.
+func visibleWidth(line string) int {
+	if parts := strings.Split(line, "`"); balancedBackticks(parts) {
+		line = strings.Join(parts, "")
+	}
+	return len([]rune(line))
+}
+
+const (
+	alignLeft = iota
+	alignCenter
+	alignRight
+)
+
+// tableLen returns the number of lines of the GFM table the lines start
+// with: a `|` header row, a `|---|:-:|` separator row then `|` rows, or 0
+// if they don't start with one.
+func tableLen(lines []string) int {
+	if len(lines) < 2 || !isTableRow(lines[0]) || tableAligns(lines[1]) == nil {
+		return 0
+	}
+
+	n := 2
+	for n < len(lines) && isTableRow(lines[n]) {
+		n++
+	}
+	return n
+}
+
+func isTableRow(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+// tableAligns returns the alignments of the columns of a table separator
+// row, or nil if the line isn't one.
+func tableAligns(line string) []int {
+	if !isTableRow(line) {
+		return nil
+	}
+
+	var aligns []int
+	for _, cell := range splitTableRow(line) {
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		dashes := strings.Trim(cell, ":")
+		if dashes == "" || strings.Trim(dashes, "-") != "" {
+			return nil
+		}
+
+		switch {
+		case left && right:
+			aligns = append(aligns, alignCenter)
+		case right:
+			aligns = append(aligns, alignRight)
+		default:
+			aligns = append(aligns, alignLeft)
+		}
+	}
+	return aligns
+}
+
+// splitTableRow returns the trimmed cells of a table row, `\|` being a
+// pipe within a cell.
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	cells := strings.Split(strings.ReplaceAll(line, `\|`, "\x00"), "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(strings.ReplaceAll(cell, "\x00", "|"))
+	}
+	return cells
+}
+
+// renderTable renders the lines of a table with aligned columns, the header
+// as a title.
+func (r *Renderer) renderTable(lines []string) []string {
+	aligns := tableAligns(lines[1])
+	rows := [][]string{splitTableRow(lines[0])}
+	for _, line := range lines[2:] {
+		rows = append(rows, splitTableRow(line))
+	}
+
+	widths := make([]int, len(aligns))
+	for _, row := range rows {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			if w := visibleWidth(row[i]); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	var out []string
+	for i, row := range rows {
+		cells := make([]string, len(widths))
+		for j := range widths {
+			var cell string
+			if j < len(row) {
+				cell = row[j]
+			}
+
+			text := r.renderInlineCode(cell)
+			if i == 0 {
+				text = r.theme.Paint(ElemTitle, cell)
+			}
+			cells[j] = pad(text, widths[j]-visibleWidth(cell), aligns[j])
+		}
+		out = append(out, strings.TrimRight("  "+strings.Join(cells, "  "), " "))
+
+		if i == 0 {
+			dashes := make([]string, len(widths))
+			for j, w := range widths {
+				dashes[j] = strings.Repeat("-", w)
+			}
+			out = append(out, "  "+strings.Join(dashes, "  "))
+		}
+	}
+	return out
+}
+
+// pad pads the text with n spaces per the alignment.
+func pad(text string, n, align int) string {
+	switch align {
+	case alignRight:
+		return strings.Repeat(" ", n) + text
+	case alignCenter:
+		return strings.Repeat(" ", n/2) + text + strings.Repeat(" ", n-n/2)
+	default:
+		return text + strings.Repeat(" ", n)
+	}
+}
+
+func (r *Renderer) write(lines []string) error {
+	_, err := fmt.Fprintln(r.out, "\n"+strings.TrimRight(strings.Join(lines, "\n"), "\n")+"\n")
+	return err
+}
+
+// renderCode colors a command, highlighting its `{{placeholders}}` without
+// the braces.
+func (r *Renderer) renderCode(code string) string {
+	var b strings.Builder
+	for _, segment := range SplitPlaceholders(code) {
+		if segment.Placeholder {
+			b.WriteString(r.theme.Paint(ElemPlaceholder, segment.Text))
+		} else {
+			b.WriteString(r.theme.Paint(ElemCode, segment.Text))
+		}
+	}
+	return b.String()
+}
+
+// theme returns the theme of the native renderer, colorless for plain
+// output or when not coloring, see UseColor.
+func (e *Executor) theme() Theme {
+	if e.plain || !UseColor(e.color) {
+		return Theme{}
+	}
+	return NewTheme(e.cfg.Theme)
+}
+
+// renderBat renders the cheat-sheet file with bat, installed as `bat` or
+// `batcat` on Debian, or else with the native renderer.
+func (e *Executor) renderBat(path string) error {
+	if !e.batChecked {
+		for _, name := range []string{"bat", "batcat"} {
+			if batPath, err := exec.LookPath(name); err == nil {
+				e.batPath = batPath
+				break
+			}
+		}
+		e.batChecked = true
+	}
+
+	if e.batPath == "" {
+		if e.printLog {
+			log.Printf("bat not found, render natively\n")
+		}
+		return NewRenderer(e.theme(), os.Stdout).RenderFile(path)
+	}
+
+	color := ColorNever
+	if UseColor(e.color) {
+		color = ColorAlways
+	}
+
+	batCmd := exec.CommandContext(e.ctx, e.batPath, "--language", "md", "--style", "plain", "--color", color, path)
+	batCmd.Stdin = os.Stdin
+	batCmd.Stdout = os.Stdout
+	batCmd.Stderr = os.Stderr
+	return batCmd.Run()
+}
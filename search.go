@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const highlightCode = "1;31"
+
+// SearchMatch is a line of a cheat-sheet matching a search, with the spans
+// to highlight.
+type SearchMatch struct {
+	Topic string
+	Line  int
+	Text  string
+	Spans [][2]int
+}
+
+// NewSearchPattern compiles the query, a literal string unless regex is set,
+// to match case-insensitively.
+func NewSearchPattern(query string, regex bool) (*regexp.Regexp, error) {
+	if !regex {
+		query = regexp.QuoteMeta(query)
+	}
+	return regexp.Compile("(?i)" + query)
+}
+
+// MatchSpans returns the spans of the line to highlight: the capture groups
+// of the pattern if it has some, else the whole matches.
+func MatchSpans(re *regexp.Regexp, line string) [][2]int {
+	var spans [][2]int
+	for _, loc := range re.FindAllStringSubmatchIndex(line, -1) {
+		if len(loc) == 2 {
+			if loc[0] < loc[1] {
+				spans = append(spans, [2]int{loc[0], loc[1]})
+			}
+			continue
+		}
+
+		for i := 2; i+1 < len(loc); i += 2 {
+			if loc[i] >= 0 && loc[i] < loc[i+1] {
+				spans = append(spans, [2]int{loc[i], loc[i+1]})
+			}
+		}
+	}
+	return spans
+}
+
+// SearchFile returns the lines of the file matching the pattern, at most
+// limit of them unless limit is negative.
+func SearchFile(topic, path string, re *regexp.Regexp, limit int) ([]SearchMatch, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return SearchReader(topic, f, re, limit)
+}
+
+// SearchReader is SearchFile of the lines read from r.
+func SearchReader(topic string, r io.Reader, re *regexp.Regexp, limit int) ([]SearchMatch, error) {
+	var matches []SearchMatch
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan() && limit != 0; n++ {
+		line := scanner.Text()
+		if !re.MatchString(line) {
+			continue
+		}
+
+		matches = append(matches, SearchMatch{
+			Topic: topic,
+			Line:  n,
+			Text:  line,
+			Spans: MatchSpans(re, line),
+		})
+		limit--
+	}
+	return matches, scanner.Err()
+}
+
+// Highlight colors the spans of the line.
+func Highlight(line string, spans [][2]int) string {
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		if span[0] < last {
+			continue
+		}
+
+		b.WriteString(line[last:span[0]])
+		b.WriteString("\x1b[" + highlightCode + "m" + line[span[0]:span[1]] + "\x1b[0m")
+		last = span[1]
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+func (m *SearchMatch) Write(w io.Writer, color bool) error {
+	text := m.Text
+	if color {
+		text = Highlight(text, m.Spans)
+	}
+
+	_, err := fmt.Fprintf(w, "%s:%d: %s\n", m.Topic, m.Line, text)
+	return err
+}
+
+// Search prints the lines of local cheat-sheets matching the query.
+func (e *Executor) Search(cmd *Command) error {
+	sheets, err := LocalCheatSheets(e.cfg)
+	if err != nil {
+		return err
+	}
+
+	return e.search(cmd, sheets)
+}
+
+// GrepCache prints the lines of the pages of the tldr cache matching the
+// query, labelled `page/topic`. Every configured page directory is searched,
+// even for topics also in an earlier one.
+func (e *Executor) GrepCache(cmd *Command) error {
+	var sheets []CheatSheet
+	for _, page := range e.cfg.TldrPages {
+		pageSheets, err := CachedCheatSheets(e.cfg.TldrCachePath, []string{page})
+		if err != nil {
+			return err
+		}
+
+		for _, sheet := range pageSheets {
+			sheet.Topic = page + "/" + sheet.Topic
+			sheets = append(sheets, sheet)
+		}
+	}
+
+	return e.search(cmd, sheets)
+}
+
+func (e *Executor) search(cmd *Command, sheets []CheatSheet) error {
+	re, err := NewSearchPattern(cmd.Args[0], cmd.HasFlag(RegexFlag))
+	if err != nil {
+		return fmt.Errorf("invalid search pattern: %w", err)
+	}
+
+	limit, err := cmd.IntFlag(LimitFlag, -1)
+	if err != nil {
+		return err
+	}
+
+	// Matches are in the order of the cheat-sheets then of their lines, the
+	// limit applying after reversing it.
+	reverse := cmd.HasFlag(ReverseFlag)
+	var matches []SearchMatch
+	for _, sheet := range sheets {
+		if limit >= 0 && len(matches) >= limit && !reverse {
+			break
+		}
+
+		found, err := e.searchSheet(sheet, re)
+		if err != nil {
+			return err
+		}
+		matches = append(matches, found...)
+	}
+
+	if reverse {
+		Reverse(matches)
+	}
+
+	if limit >= 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	color := UseColor(cmd.Flags[ColorFlag])
+	for _, m := range matches {
+		if err := m.Write(os.Stdout, color); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchSheet returns the lines of the cheat-sheet matching the pattern,
+// decrypting encrypted ones.
+func (e *Executor) searchSheet(sheet CheatSheet, re *regexp.Regexp) ([]SearchMatch, error) {
+	if !IsEncrypted(sheet.Path) {
+		return SearchFile(sheet.Topic, sheet.Path, re, -1)
+	}
+
+	data, err := e.readSheet(sheet.Path)
+	if err != nil {
+		return nil, err
+	}
+	return SearchReader(sheet.Topic, bytes.NewReader(data), re, -1)
+}
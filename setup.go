@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+const setupOfferedFilename = "setup-offered"
+
+// Setup asks for the editor, tldr page sets and language, writes them to the
+// config file, then populates the tldr cache if wished.
+func (e *Executor) Setup() error {
+	exists, err := IsFileExists(filepath.Dir(e.cfg.Path), filepath.Base(e.cfg.Path))
+	if err != nil {
+		return err
+	}
+
+	if exists && !e.confirm(true, "overwrite config file '%v'?", e.cfg.Path) {
+		return fmt.Errorf("config file '%v' kept, change it with 'cs --edit-config'", e.cfg.Path)
+	}
+
+	p, err := OpenPrompter()
+	if err != nil {
+		return fmt.Errorf("no terminal for the setup: %w", err)
+	}
+	defer p.Close()
+
+	e.cfg.EditorPath = p.Ask(e.cfg.EditorPath, "editor [%v]: ", e.cfg.EditorPath)
+	pages := p.Ask(strings.Join(e.cfg.TldrPages, ","), "tldr page sets, comma-separated, e.g. common,linux,osx [%v]: ",
+		strings.Join(e.cfg.TldrPages, ","))
+	e.cfg.TldrPages = nil
+	for _, page := range strings.Split(pages, ",") {
+		if page = strings.TrimSpace(page); page != "" {
+			e.cfg.TldrPages = append(e.cfg.TldrPages, page)
+		}
+	}
+
+	e.cfg.Lang = p.Ask(e.cfg.Lang, "language of tldr pages, e.g. zh, or en for english [%v]: ", e.cfg.Lang)
+
+	if err := e.writeSetupConfig(); err != nil {
+		return err
+	}
+	fmt.Printf("wrote config file '%v', change it with 'cs --edit-config'\n", e.cfg.Path)
+
+	if p.AskYes(true, "populate the tldr cache now?") {
+		return e.update()
+	}
+	return nil
+}
+
+func (e *Executor) writeSetupConfig() error {
+	settings := map[string]any{
+		"editor":     e.cfg.EditorPath,
+		"tldr_pages": e.cfg.TldrPages,
+	}
+	if e.cfg.Lang != "" {
+		settings["lang"] = e.cfg.Lang
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if filepath.Ext(e.cfg.Path) == ".toml" {
+		err = toml.NewEncoder(&buf).Encode(settings)
+	} else {
+		err = yaml.NewEncoder(&buf).Encode(settings)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(e.cfg.Path), e.cfg.DirPerm()); err != nil {
+		return err
+	}
+	return WriteFileAtomic(e.cfg.Path, buf.Bytes(), e.cfg.FilePerm())
+}
+
+// OfferSetup offers the setup once, on the first interactive run without a
+// config file.
+func (e *Executor) OfferSetup() error {
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil
+	}
+
+	if ok, err := IsFileExists(filepath.Dir(e.cfg.Path), filepath.Base(e.cfg.Path)); ok || err != nil {
+		return err
+	}
+
+	if ok, err := IsFileExists(e.cfg.DataDir, setupOfferedFilename); ok || err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(e.cfg.DataDir, e.cfg.DirPerm()); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(e.cfg.DataDir, setupOfferedFilename), nil, e.cfg.FilePerm()); err != nil {
+		return err
+	}
+
+	p, err := OpenPrompter()
+	if err != nil {
+		return err
+	}
+
+	setup := p.AskYes(true, "no config file yet, set up editor, tldr pages and language now? 'cs --setup' does it later")
+	p.Close()
+	if !setup {
+		return nil
+	}
+
+	if err := e.Setup(); err != nil {
+		return err
+	}
+
+	if e.printLog {
+		log.Printf("setup done, go on with the command\n")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const shadowsWarnedFilename = "shadows-warned"
+
+// Shadows prints the local cheat-sheets that shadow a page of the tldr
+// cache, with the page.
+func (e *Executor) Shadows() error {
+	sheets, err := LocalCheatSheets(e.cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, sheet := range sheets {
+		page, err := e.findCachedTopic(sheet.Topic)
+		if err != nil {
+			return err
+		}
+
+		if page != "" {
+			fmt.Printf("%v\t%v\n", sheet.Topic, page)
+		}
+	}
+	return nil
+}
+
+// warnShadow warns, once per topic, that the local cheat-sheet found shadows
+// a page of the tldr cache. Failures are only logged as it is auxiliary.
+func (e *Executor) warnShadow(cmd *Command) {
+	if !e.cfg.WarnShadows {
+		return
+	}
+
+	if err := e.warnShadowOnce(cmd.Topic()); err != nil && cmd.PrintLog() {
+		log.Printf("warn about shadowed page failed: %v\n", err)
+	}
+}
+
+func (e *Executor) warnShadowOnce(topic string) error {
+	page, err := e.findCachedTopic(topic)
+	if err != nil || page == "" {
+		return err
+	}
+
+	path := filepath.Join(e.cfg.DataDir, shadowsWarnedFilename)
+	warned, err := readLines(path)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range warned {
+		if t == topic {
+			return nil
+		}
+	}
+
+	warnf("your cheat-sheet '%v' shadows the tldr page '%v', see 'cs --shadows'", topic, page)
+	return e.cfg.AppendState(path, []byte(topic))
+}
+
+// readLines returns the lines of the file, none if it doesn't exist.
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
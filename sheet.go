@@ -0,0 +1,425 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+type CheatSheet struct {
+	Topic string
+	Path  string
+	// Local is set for cheat-sheets of the cheat-sheets directory, as
+	// opposed to pages of the tldr cache.
+	Local bool
+}
+
+// LocalCheatSheets lists cheat-sheets in the cheat-sheets directory sorted
+// by topic, leaving out files excluded by `.csignore`. Encrypted ones, e.g.
+// `secrets.md.enc`, are listed by their topic too. When a topic has files
+// with several recognized extensions, the configured extension wins.
+func LocalCheatSheets(cfg *Config) ([]CheatSheet, error) {
+	entries, err := os.ReadDir(cfg.CheatSheetsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := LoadIgnoreRules(cfg.CheatSheetsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	exts := cfg.Extensions()
+	found := make(map[string]CheatSheet)
+	rank := make(map[string]int)
+	for _, entry := range entries {
+		if !isRegularFile(cfg.CheatSheetsDir, entry) || rules.Ignored(entry.Name(), false) {
+			continue
+		}
+
+		for i, ext := range exts {
+			suffix := "." + ext
+			if strings.HasSuffix(entry.Name(), suffix+EncryptedExt) {
+				suffix += EncryptedExt
+			}
+			if !strings.HasSuffix(entry.Name(), suffix) || entry.Name() == suffix {
+				continue
+			}
+
+			topic := strings.TrimSuffix(entry.Name(), suffix)
+			if r, ok := rank[topic]; !ok || i < r {
+				found[topic] = CheatSheet{
+					Topic: topic,
+					Path:  filepath.Join(cfg.CheatSheetsDir, entry.Name()),
+					Local: true,
+				}
+				rank[topic] = i
+			}
+			break
+		}
+	}
+
+	sheets := make([]CheatSheet, 0, len(found))
+	for _, sheet := range found {
+		sheets = append(sheets, sheet)
+	}
+
+	sort.Slice(sheets, func(i, j int) bool {
+		return sheets[i].Topic < sheets[j].Topic
+	})
+	return sheets, nil
+}
+
+// isRegularFile reports whether the entry is a file, following symlinks.
+// Dangling symlinks and symlink loops aren't files.
+func isRegularFile(dirname string, entry os.DirEntry) bool {
+	if entry.Type()&os.ModeSymlink == 0 {
+		return entry.Type().IsRegular()
+	}
+
+	info, err := os.Stat(filepath.Join(dirname, entry.Name()))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// ParseDescription returns the description of a cheat-sheet file. Encrypted
+// cheat-sheets have none, so that listing them doesn't prompt for the
+// passphrase.
+func ParseDescription(path string) (string, error) {
+	if IsEncrypted(path) {
+		return "", nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return SheetDescription(string(data)), nil
+}
+
+// SheetDescription returns the description of a cheat-sheet, taken from a
+// `description:` front-matter key or else the first tldr `>` line.
+func SheetDescription(content string) string {
+	meta, body := ParseFrontMatter(content)
+	if desc, ok := meta["description"]; ok {
+		return desc
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		if val, ok := cutPrefix(strings.TrimSpace(line), ">"); ok {
+			return strings.TrimSpace(val)
+		}
+	}
+
+	return ""
+}
+
+// ParseFrontMatter splits a leading `---` delimited block of `key: value`
+// lines from the content, returning its values and the remaining body.
+// Content without front-matter is returned as is.
+func ParseFrontMatter(content string) (map[string]string, string) {
+	meta := make(map[string]string)
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return meta, content
+	}
+
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "---" {
+			return meta, strings.Join(lines[i+1:], "\n")
+		}
+
+		key, val, ok := strings.Cut(line, ":")
+		if ok {
+			meta[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(val), `"'`)
+		}
+	}
+
+	// Not closed, so not front-matter.
+	return make(map[string]string), content
+}
+
+// StripFrontMatter returns the content without its front-matter.
+func StripFrontMatter(content string) string {
+	_, body := ParseFrontMatter(content)
+	return body
+}
+
+// ParseTags returns the tags of a front-matter value, either `a, b` or
+// `[a, b]`.
+func ParseTags(val string) []string {
+	var tags []string
+	for _, tag := range strings.Split(strings.Trim(val, "[]"), ",") {
+		if tag = strings.Trim(strings.TrimSpace(tag), `"'`); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// HasTag reports whether the front-matter of the cheat-sheet file lists the
+// tag. Tags of encrypted cheat-sheets aren't read, like their descriptions.
+func HasTag(path, tag string) (bool, error) {
+	if IsEncrypted(path) {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	meta, _ := ParseFrontMatter(string(data))
+	for _, t := range ParseTags(meta["tags"]) {
+		if strings.EqualFold(t, tag) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+type Example struct {
+	Description string
+	Command     string
+}
+
+// Sheet is a cheat-sheet in the tldr format: a `#` title, `>` description
+// lines, then examples made of a `-` description and a backticked command.
+type Sheet struct {
+	Title       string
+	Description []string
+	Examples    []Example
+}
+
+func ParseSheet(content string) *Sheet {
+	sheet := &Sheet{}
+	var example *Example
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "# ") && sheet.Title == "":
+			sheet.Title = strings.TrimSpace(line[2:])
+		case strings.HasPrefix(line, ">") && len(sheet.Examples) == 0:
+			sheet.Description = append(sheet.Description, strings.TrimSpace(line[1:]))
+		case strings.HasPrefix(line, "- "):
+			sheet.Examples = append(sheet.Examples, Example{Description: strings.TrimSpace(line[2:])})
+			example = &sheet.Examples[len(sheet.Examples)-1]
+		case strings.HasPrefix(line, "`") && example != nil && example.Command == "":
+			example.Command = strings.Trim(line, "`")
+		}
+	}
+
+	return sheet
+}
+
+func (s *Sheet) HasCommand(command string) bool {
+	for _, example := range s.Examples {
+		if example.Command == command {
+			return true
+		}
+	}
+	return false
+}
+
+func (ex Example) String() string {
+	return fmt.Sprintf("- %s\n\n`%s`\n", ex.Description, ex.Command)
+}
+
+// IsBinary reports whether the data isn't text, having null bytes or not
+// being valid UTF-8, so it can't be a cheat-sheet.
+func IsBinary(data []byte) bool {
+	return bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data)
+}
+
+// ValidateSheet checks the content is a cheat-sheet we can render.
+func ValidateSheet(content string) error {
+	sheet := ParseSheet(content)
+	if sheet.Title == "" {
+		return errors.New("cheat-sheet has no '#' title line")
+	}
+
+	for i, example := range sheet.Examples {
+		if example.Command == "" {
+			return fmt.Errorf("example %d '%v' has no command", i+1, example.Description)
+		}
+	}
+	return nil
+}
+
+// SelectExamples prefixes the description of each example of the content
+// with its number, e.g. `- [3] List files`, if numbered, and keeps only the
+// only-th example, besides the title and description, unless only is 0.
+func SelectExamples(content string, numbered bool, only int) (string, error) {
+	lines := strings.Split(content, "\n")
+	var kept []string
+	n := 0
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "- ") {
+			n++
+			if numbered {
+				line = fmt.Sprintf("- [%d] %s", n, strings.TrimSpace(trimmed[2:]))
+			}
+		}
+
+		if only == 0 || n == 0 || n == only {
+			kept = append(kept, line)
+		}
+	}
+
+	if only < 0 || only > n {
+		return "", fmt.Errorf("no example %d, the cheat-sheet has %d", only, n)
+	}
+	return strings.Join(kept, "\n"), nil
+}
+
+// Substitute replaces the tokens of the substitutions in the content,
+// preferring longer tokens when they overlap, e.g. `$PROJECT` over `$PROJ`.
+func Substitute(content string, substitutions map[string]string) string {
+	tokens := make([]string, 0, len(substitutions))
+	for token := range substitutions {
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		if len(tokens[i]) != len(tokens[j]) {
+			return len(tokens[i]) > len(tokens[j])
+		}
+		return tokens[i] < tokens[j]
+	})
+
+	var oldnew []string
+	for _, token := range tokens {
+		oldnew = append(oldnew, token, substitutions[token])
+	}
+	return strings.NewReplacer(oldnew...).Replace(content)
+}
+
+// Retitle replaces the `#` title line of the content, or adds one.
+func Retitle(content, title string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "# ") {
+			lines[i] = "# " + title
+			return strings.Join(lines, "\n")
+		}
+	}
+	return "# " + title + "\n\n" + content
+}
+
+// Heading is a markdown heading, of level 1 for `#` to 6 for `######`.
+type Heading struct {
+	Level int
+	Text  string
+}
+
+// ParseHeadings returns the headings of the markdown content in order,
+// leaving out lines of fenced code blocks.
+func ParseHeadings(content string) []Heading {
+	var headings []Heading
+	fenced := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			fenced = !fenced
+			continue
+		}
+
+		level := len(line) - len(strings.TrimLeft(line, "#"))
+		if fenced || level == 0 || level > 6 || len(line) > level && line[level] != ' ' {
+			continue
+		}
+
+		if text := strings.TrimSpace(strings.TrimRight(line[level:], "#")); text != "" {
+			headings = append(headings, Heading{Level: level, Text: text})
+		}
+	}
+	return headings
+}
+
+// FindSection returns the 1-based line number of the first heading matching
+// the query, case-insensitively, or else of the first matching example
+// description. It returns 0 when nothing matches.
+func FindSection(content, query string) int {
+	query = strings.ToLower(query)
+	lines := strings.Split(content, "\n")
+	for _, prefix := range []string{"#", "- "} {
+		for i, line := range lines {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, prefix) && strings.Contains(strings.ToLower(line), query) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// StripNotes removes the notes of the content, which are kept in the file
+// but not rendered: `<!-- -->` comments, possibly spanning lines, and lines
+// starting with `//`. Fenced code blocks are left as is. Lines holding only
+// notes are removed altogether.
+func StripNotes(content string) string {
+	var kept []string
+	fenced, comment := false, false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !comment && strings.HasPrefix(trimmed, "```") {
+			fenced = !fenced
+		}
+
+		if fenced {
+			kept = append(kept, line)
+			continue
+		}
+
+		if !comment && strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+
+		var stripped string
+		stripped, comment = stripComments(line, comment)
+		if stripped == line {
+			kept = append(kept, line)
+		} else if stripped = strings.TrimRight(stripped, " \t"); strings.TrimSpace(stripped) != "" {
+			kept = append(kept, stripped)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// stripComments removes the `<!-- -->` comments of the line, the line
+// starting inside one if comment. It reports whether the line ends inside
+// one.
+func stripComments(line string, comment bool) (string, bool) {
+	var b strings.Builder
+	for {
+		if comment {
+			_, after, ok := strings.Cut(line, "-->")
+			if !ok {
+				return b.String(), true
+			}
+			line, comment = after, false
+		}
+
+		before, after, ok := strings.Cut(line, "<!--")
+		b.WriteString(before)
+		if !ok {
+			return b.String(), false
+		}
+		line, comment = after, true
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLocalCheatSheetsSymlinks(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.CheatSheetsDir = symlinkedTempDir(t)
+	writeSheet(t, cfg, "git", "# git\n")
+
+	// Cheat-sheets kept elsewhere, e.g. in a dotfiles repo, linked in.
+	elsewhere := filepath.Join(symlinkedTempDir(t), "tar.md")
+	if err := os.WriteFile(elsewhere, []byte("# tar\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, target := range map[string]string{
+		"tar.md":     elsewhere,
+		"missing.md": filepath.Join(cfg.CheatSheetsDir, "nowhere.md"),
+		"loop.md":    filepath.Join(cfg.CheatSheetsDir, "loop.md"),
+		"subdir.md":  t.TempDir(),
+	} {
+		if err := os.Symlink(target, filepath.Join(cfg.CheatSheetsDir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sheets, err := LocalCheatSheets(cfg)
+	if err != nil {
+		t.Fatalf("LocalCheatSheets failed: %v", err)
+	}
+
+	var topics []string
+	for _, sheet := range sheets {
+		topics = append(topics, sheet.Topic)
+	}
+	if want := []string{"git", "tar"}; !reflect.DeepEqual(topics, want) {
+		t.Errorf("topics %v, want %v", topics, want)
+	}
+}
+
+func TestWriteFileAtomicSymlinkedSheet(t *testing.T) {
+	cfg := testConfig(t)
+
+	// A cheat-sheet kept elsewhere, e.g. in Dropbox, linked in.
+	target := filepath.Join(symlinkedTempDir(t), "git.md")
+	if err := os.WriteFile(target, []byte("# git\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(cfg.CheatSheetsDir, "git.md")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFileAtomic(link, []byte("# git\n\n- Status:\n"), 0644); err != nil {
+		t.Fatalf("WriteFileAtomic failed: %v", err)
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("link replaced by a %v file", info.Mode())
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "# git\n\n- Status:\n"; got != want {
+		t.Errorf("target %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const siteIndexFilename = "index.html"
+
+const siteCSS = `body { font-family: sans-serif; max-width: 50em; margin: 2em auto; padding: 0 1em; color: #222; }
+a { color: #0366d6; text-decoration: none; }
+blockquote { margin: 0; color: #555; }
+li { margin: 1em 0; list-style: none; }
+code { display: block; margin-top: .3em; padding: .4em .6em; background: #f4f4f4; border-radius: 3px; }
+var { color: #0366d6; font-style: normal; }`
+
+var siteSheetTemplate = template.Must(template.New("sheet").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>{{.CSS}}</style>
+</head>
+<body>
+<p><a href="index.html">&larr; all cheat-sheets</a></p>
+<h1>{{.Title}}</h1>
+{{range .Description}}<blockquote>{{.}}</blockquote>
+{{end}}<ul>
+{{range .Examples}}<li>{{.Description}}<code>{{range .Code}}{{if .Placeholder}}<var>{{.Text}}</var>{{else}}{{.Text}}{{end}}{{end}}</code></li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+var siteIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Cheat-sheets</title>
+<style>{{.CSS}}</style>
+</head>
+<body>
+<h1>Cheat-sheets</h1>
+<ul>
+{{range .Entries}}<li><a href="{{.Href}}">{{.Topic}}</a>{{if .Description}} &mdash; {{.Description}}{{end}}</li>
+{{end}}</ul>
+</body>
+</html>
+`))
+
+// CodeSegment is a part of an example command, either literal text or a
+// `{{placeholder}}` without its braces.
+type CodeSegment struct {
+	Text        string
+	Placeholder bool
+}
+
+// SplitPlaceholders splits a command into literal and placeholder segments.
+func SplitPlaceholders(code string) []CodeSegment {
+	var segments []CodeSegment
+	for {
+		start := strings.Index(code, "{{")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(code[start:], "}}")
+		if end < 0 {
+			break
+		}
+		end += start
+
+		if start > 0 {
+			segments = append(segments, CodeSegment{Text: code[:start]})
+		}
+		segments = append(segments, CodeSegment{Text: code[start+2 : end], Placeholder: true})
+		code = code[end+2:]
+	}
+
+	if code != "" {
+		segments = append(segments, CodeSegment{Text: code})
+	}
+	return segments
+}
+
+type siteExample struct {
+	Description string
+	Code        []CodeSegment
+}
+
+type siteEntry struct {
+	Topic       string
+	Href        string
+	Description string
+}
+
+// RenderHTML renders a tldr-markdown cheat-sheet as a standalone html page.
+func RenderHTML(content, topic string) ([]byte, error) {
+	sheet := ParseSheet(content)
+	title := sheet.Title
+	if title == "" {
+		title = topic
+	}
+
+	var examples []siteExample
+	for _, example := range sheet.Examples {
+		examples = append(examples, siteExample{
+			Description: example.Description,
+			Code:        SplitPlaceholders(example.Command),
+		})
+	}
+
+	var buf bytes.Buffer
+	err := siteSheetTemplate.Execute(&buf, map[string]any{
+		"Title":       title,
+		"CSS":         template.CSS(siteCSS),
+		"Description": sheet.Description,
+		"Examples":    examples,
+	})
+	return buf.Bytes(), err
+}
+
+// BuildSite renders every local cheat-sheet, as rendered by BatchRender, to an
+// html page of the directory next to an index linking them. Pages are
+// rewritten on every build and those of cheat-sheets since removed or renamed
+// are deleted, so building again gives the same site.
+func (e *Executor) BuildSite(cmd *Command) error {
+	dir := cmd.Args[0]
+	if err := os.MkdirAll(dir, e.cfg.DirPerm()); err != nil {
+		return err
+	}
+
+	sheets, err := LocalCheatSheets(e.cfg)
+	if err != nil {
+		return err
+	}
+
+	var entries []siteEntry
+	for _, sheet := range sheets {
+		content, err := e.renderedContent(cmd, sheet.Path)
+		if err != nil {
+			return err
+		}
+
+		page, err := RenderHTML(content, sheet.Topic)
+		if err != nil {
+			return err
+		}
+
+		filename := sheet.Topic + ".html"
+		if err := WriteFileAtomic(filepath.Join(dir, filename), page, e.cfg.FilePerm()); err != nil {
+			return err
+		}
+
+		entries = append(entries, siteEntry{
+			Topic:       sheet.Topic,
+			Href:        filename,
+			Description: SheetDescription(content),
+		})
+	}
+
+	var buf bytes.Buffer
+	err = siteIndexTemplate.Execute(&buf, map[string]any{
+		"CSS":     template.CSS(siteCSS),
+		"Entries": entries,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := WriteFileAtomic(filepath.Join(dir, siteIndexFilename), buf.Bytes(), e.cfg.FilePerm()); err != nil {
+		return err
+	}
+
+	if err := removeStalePages(dir, entries); err != nil {
+		return err
+	}
+
+	if cmd.PrintLog() {
+		log.Printf("built %d pages into '%v'\n", len(entries), dir)
+	}
+	return nil
+}
+
+// removeStalePages deletes the html pages of the directory other than the
+// index and those of the entries.
+func removeStalePages(dir string, entries []siteEntry) error {
+	keep := map[string]bool{siteIndexFilename: true}
+	for _, entry := range entries {
+		keep[entry.Href] = true
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".html" || keep[file.Name()] {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, file.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestBuildSite(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Substitutions = map[string]string{"$PROJECT": "cs"}
+	writeSheet(t, cfg, "proj", "# proj\n\n> Builds it.\n\n<!-- private -->\n- Build:\n\n`go build -o $PROJECT`\n")
+	old := writeSheet(t, cfg, "old", "# old\n")
+
+	dir := t.TempDir()
+	build := func() {
+		t.Helper()
+		cmd := NewCommand(CmdBuildSite, WithArgs([]string{dir}))
+		if err := NewExecutor(cfg, WithTldr(&fakeTldr{})).Exec(context.Background(), cmd); err != nil {
+			t.Fatalf("BuildSite failed: %v", err)
+		}
+	}
+
+	build()
+	if err := os.Remove(old); err != nil {
+		t.Fatal(err)
+	}
+	build()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	if want := []string{"index.html", "proj.html"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("site files %v, want %v", names, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "proj.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	page := string(data)
+	if !strings.Contains(page, "go build -o cs") {
+		t.Errorf("page %q, want $PROJECT substituted", page)
+	}
+	if strings.Contains(page, "private") {
+		t.Errorf("page %q, want notes left out", page)
+	}
+}
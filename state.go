@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io"
+	"os"
+)
+
+// WriteState replaces a state file of the data directory, e.g. a cache of
+// the tool, atomically so a crash leaves either the old or the new content.
+func (c *Config) WriteState(path string, data []byte) error {
+	if err := os.MkdirAll(c.DataDir, c.DirPerm()); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data, c.FilePerm(), c.FsyncState)
+}
+
+// AppendState appends a line to a state log of the data directory. A last
+// line left partial by a crash is ended first, so that only it is lost,
+// readers skipping lines they can't parse.
+func (c *Config) AppendState(path string, line []byte) error {
+	if err := os.MkdirAll(c.DataDir, c.DirPerm()); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, c.FilePerm())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data := append(line, '\n')
+	if partial, err := endsPartialLine(f); err != nil {
+		return err
+	} else if partial {
+		data = append([]byte{'\n'}, data...)
+	}
+
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+
+	if c.FsyncState {
+		return f.Sync()
+	}
+	return nil
+}
+
+// endsPartialLine reports whether the file isn't empty and lacks a final
+// newline.
+func endsPartialLine(f *os.File) (bool, error) {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return false, err
+	}
+
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil && err != io.EOF {
+		return false, err
+	}
+	return last[0] != '\n', nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAppendStateEndsPartialLine(t *testing.T) {
+	cfg := testConfig(t)
+	path := usagePath(cfg)
+	// A crash while appending left the last record partial.
+	if err := os.WriteFile(path, []byte(`{"t":"git","at":100}`+"\n"+`{"t":"doc`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RecordUsage(cfg, "tar", time.Unix(200, 0)); err != nil {
+		t.Fatalf("RecordUsage failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"t":"git","at":100}` + "\n" + `{"t":"doc` + "\n" + `{"t":"tar","at":200}` + "\n"
+	if string(data) != want {
+		t.Errorf("usage log is %q, want %q", data, want)
+	}
+
+	usages, err := LoadUsage(cfg)
+	if err != nil {
+		t.Fatalf("LoadUsage failed: %v", err)
+	}
+
+	var topics []string
+	for _, usage := range usages {
+		topics = append(topics, usage.Topic)
+	}
+	if want := []string{"git", "tar"}; !reflect.DeepEqual(topics, want) {
+		t.Errorf("loaded topics %v, want %v", topics, want)
+	}
+}
+
+func TestLoadUsageSkipsCorruptLines(t *testing.T) {
+	cfg := testConfig(t)
+	log := "" +
+		`{"t":"git","at":100}` + "\n" +
+		"\x00\x00\x00\n" +
+		`{"at":150}` + "\n" +
+		"\n" +
+		`{"t":"git","at":300}` + "\n" +
+		`{"t":"tar","at":2`
+	if err := os.WriteFile(usagePath(cfg), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	usages, err := LoadUsage(cfg)
+	if err != nil {
+		t.Fatalf("LoadUsage failed: %v", err)
+	}
+
+	want := []TopicUsage{{Topic: "git", Count: 2, LastUsed: time.Unix(300, 0)}}
+	if !reflect.DeepEqual(usages, want) {
+		t.Errorf("LoadUsage = %+v, want %+v", usages, want)
+	}
+}
+
+func TestReadReleaseTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), releaseCacheFilename)
+	if err := os.WriteFile(path, []byte(`{"tag_name":"v1.2`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readRelease(path); !errors.Is(err, errCorruptRelease) {
+		t.Errorf("readRelease = %v, want %v", err, errCorruptRelease)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+type Stats struct {
+	Total       int   `json:"total"`
+	Bytes       int64 `json:"bytes"`
+	TldrOverlap int   `json:"tldrOverlap"`
+}
+
+// CollectStats counts local cheat-sheets, their total size and how many of
+// them shadow a page of the tldr cache.
+func CollectStats(cfg *Config, tldr TldrClient) (*Stats, error) {
+	sheets, err := LocalCheatSheets(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{Total: len(sheets)}
+	for _, sheet := range sheets {
+		info, err := os.Stat(sheet.Path)
+		if err != nil {
+			return nil, err
+		}
+		stats.Bytes += info.Size()
+
+		path, err := tldr.FindFileInCache(sheet.Topic + "." + DefaultExtension)
+		if err != nil {
+			return nil, err
+		}
+
+		if path != "" {
+			stats.TldrOverlap++
+		}
+	}
+
+	return stats, nil
+}
+
+func (s *Stats) WriteTable(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "cheat-sheets:\t%d\nbytes:\t\t%d\ntldr overlap:\t%d\n",
+		s.Total, s.Bytes, s.TldrOverlap)
+	return err
+}
+
+func (s *Stats) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		SchemaVersion int `json:"schemaVersion"`
+		*Stats
+	}{JSONSchemaVersion, s})
+}
+
+// WriteProm writes the stats as gauges in the prometheus text format.
+func (s *Stats) WriteProm(w io.Writer) error {
+	gauges := []struct {
+		name string
+		help string
+		val  int64
+	}{
+		{"cheatsheet_total", "Number of local cheat-sheets.", int64(s.Total)},
+		{"cheatsheet_bytes", "Total size in bytes of local cheat-sheets.", s.Bytes},
+		{"cheatsheet_tldr_overlap", "Number of local cheat-sheets also in the tldr cache.", int64(s.TldrOverlap)},
+	}
+
+	for _, g := range gauges {
+		_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", g.name, g.help, g.name, g.name, g.val)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FormatSize formats a size in bytes for humans, e.g. `1.5MB`.
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// FormatTime formats a time in the local timezone for humans relative to now,
+// e.g. `3 days ago`, or as ISO-8601 for precision.
+func FormatTime(t, now time.Time, iso bool) string {
+	if iso {
+		return t.Local().Format(time.RFC3339)
+	}
+	return FormatAge(now.Sub(t)) + " ago"
+}
+
+// FormatAge formats a duration for humans in its largest unit, e.g. `3 days`.
+func FormatAge(d time.Duration) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s", unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+
+	switch {
+	case d < time.Minute:
+		return "less than a minute"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		return plural(int(d/time.Hour), "hour")
+	default:
+		return plural(int(d/(24*time.Hour)), "day")
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTime(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC+2", 2*60*60)
+	defer func() { time.Local = local }()
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		iso  bool
+		want string
+	}{
+		{0, false, "less than a minute ago"},
+		{59 * time.Second, false, "less than a minute ago"},
+		{time.Minute, false, "1 minute ago"},
+		{59 * time.Minute, false, "59 minutes ago"},
+		{time.Hour, false, "1 hour ago"},
+		{23*time.Hour + 59*time.Minute, false, "23 hours ago"},
+		{24 * time.Hour, false, "1 day ago"},
+		{3*24*time.Hour + 5*time.Hour, false, "3 days ago"},
+		{400 * 24 * time.Hour, false, "400 days ago"},
+		{0, true, "2024-03-10T14:00:00+02:00"},
+		{3 * 24 * time.Hour, true, "2024-03-07T14:00:00+02:00"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatTime(now.Add(-tt.ago), now, tt.iso); got != tt.want {
+			t.Errorf("FormatTime(now-%v, iso %v) = %q, want %q", tt.ago, tt.iso, got, tt.want)
+		}
+	}
+}
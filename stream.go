@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+const (
+	SourceLocal = "local"
+	SourceTldr  = "tldr"
+	// SourceAll selects both sources, with `-l --source`.
+	SourceAll = "all"
+)
+
+// JSONSchemaVersion is the `schemaVersion` of every JSON output. Field names
+// are stable; it is bumped when one is renamed or removed, or changes
+// meaning.
+const JSONSchemaVersion = 1
+
+// TopicEntry is a line of `--json-stream` output.
+type TopicEntry struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Name          string `json:"name"`
+	Source        string `json:"source"`
+	Path          string `json:"path"`
+	Description   string `json:"description"`
+}
+
+// JSONStream writes a JSON object per line for every topic, local ones then
+// those of the tldr cache not shadowed by a local one, e.g. as fzf
+// candidates. Each line is written as soon as it is known.
+func (e *Executor) JSONStream() error {
+	enc := json.NewEncoder(os.Stdout)
+	local, err := LocalCheatSheets(e.cfg)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, sheet := range local {
+		seen[sheet.Topic] = true
+		if err := e.writeTopicEntry(enc, sheet, SourceLocal); err != nil {
+			return err
+		}
+	}
+
+	cached, err := CachedCheatSheets(e.cfg.TldrCachePath, e.cfg.TldrPages)
+	if err != nil {
+		return err
+	}
+
+	for _, sheet := range cached {
+		if seen[sheet.Topic] {
+			continue
+		}
+
+		if err := e.writeTopicEntry(enc, sheet, SourceTldr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Executor) writeTopicEntry(enc *json.Encoder, sheet CheatSheet, source string) error {
+	desc, err := ParseDescription(sheet.Path)
+	if err != nil {
+		return err
+	}
+
+	return enc.Encode(TopicEntry{
+		SchemaVersion: JSONSchemaVersion,
+		Name:          sheet.Topic,
+		Source:        source,
+		Path:          sheet.Path,
+		Description:   desc,
+	})
+}
+
+// Preview renders the cheat-sheet, local or cached, without the side effects
+// of a find like recording usage, suggesting topics or running the fallback
+// command, e.g. for the preview pane of fzf.
+func (e *Executor) Preview(cmd *Command) error {
+	path, err := e.findLocalCheatSheet(cmd)
+	if err != nil {
+		return err
+	}
+
+	if path != "" {
+		return e.renderLocal(cmd, path)
+	}
+
+	if path, err = e.findCachedTopic(cmd.Topic()); err != nil {
+		return err
+	}
+
+	if path == "" {
+		return fmt.Errorf("%w: '%v'", ErrNotFound, cmd.Topic())
+	}
+	return e.render(path)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout returns what fn writes to stdout.
+func captureStdout(t *testing.T, fn func() error) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	err = fn()
+	os.Stdout = stdout
+	w.Close()
+	out := <-done
+	if err != nil {
+		t.Fatalf("failed: %v", err)
+	}
+	return out
+}
+
+func TestStatsJSONGolden(t *testing.T) {
+	var b strings.Builder
+	if err := (&Stats{Total: 3, Bytes: 120, TldrOverlap: 1}).WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "schemaVersion": 1,
+  "total": 3,
+  "bytes": 120,
+  "tldrOverlap": 1
+}
+`
+	if b.String() != want {
+		t.Errorf("WriteJSON = %v, want %v", b.String(), want)
+	}
+}
+
+func TestJSONOutputsHaveSchemaVersion(t *testing.T) {
+	cfg := testConfig(t)
+	writeSheet(t, cfg, "git", "# git\n\n> Version control.\n")
+	writeSheet(t, cfg, "tar", "# tar\n\n> Archives.\n")
+
+	for _, cmd := range []*Command{
+		NewCommand(CmdStats, WithFlag(JSONFlag, "true")),
+		NewCommand(CmdJSONStream),
+	} {
+		e := NewExecutor(cfg, WithTldr(&fakeTldr{}))
+		out := captureStdout(t, func() error { return e.Exec(context.Background(), cmd) })
+
+		dec := json.NewDecoder(strings.NewReader(out))
+		n := 0
+		for dec.More() {
+			var obj map[string]any
+			if err := dec.Decode(&obj); err != nil {
+				t.Fatalf("%v output %q isn't json: %v", cmd.Cmd, out, err)
+			}
+			n++
+
+			if v, ok := obj["schemaVersion"].(float64); !ok || int(v) != JSONSchemaVersion {
+				t.Errorf("%v object %v has schemaVersion %v, want %d", cmd.Cmd, obj, obj["schemaVersion"], JSONSchemaVersion)
+			}
+		}
+
+		if n == 0 {
+			t.Errorf("%v wrote no json object", cmd.Cmd)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultMinMatch = 0.6
+	maxSuggestions  = 5
+)
+
+// EditDistance returns the edit distance between a and b in runes, counting
+// insertions, deletions, substitutions and transpositions of adjacent runes,
+// the most common typo.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func minInt(n int, rest ...int) int {
+	for _, m := range rest {
+		if m < n {
+			n = m
+		}
+	}
+	return n
+}
+
+// Similarity returns how similar a and b are, from 0 for nothing in common
+// to 1 for equal, going by their edit distance.
+func Similarity(a, b string) float64 {
+	n := len([]rune(a))
+	if m := len([]rune(b)); m > n {
+		n = m
+	}
+
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(EditDistance(a, b))/float64(n)
+}
+
+// Suggest returns the candidates at least minMatch similar to the topic, the
+// most similar first.
+func Suggest(topic string, candidates []string, minMatch float64) []string {
+	type scored struct {
+		topic string
+		score float64
+	}
+
+	var matches []scored
+	for _, candidate := range candidates {
+		if score := Similarity(topic, candidate); score >= minMatch && candidate != topic {
+			matches = append(matches, scored{topic: candidate, score: score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].topic < matches[j].topic
+	})
+
+	var suggestions []string
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, matches[i].topic)
+	}
+	return suggestions
+}
+
+// suggest prints topics of local cheat-sheets and tldr cache pages similar to
+// the one of the command, which was found nowhere.
+func (e *Executor) suggest(cmd *Command) error {
+	minMatch := e.cfg.MinMatch
+	if val, ok := cmd.Flags[MinMatchFlag]; ok {
+		var err error
+		if minMatch, err = strconv.ParseFloat(val, 64); err != nil || minMatch < 0 || minMatch > 1 {
+			return fmt.Errorf("invalid -%v '%v', expect a number from 0 to 1", MinMatchFlag, val)
+		}
+	}
+
+	topics, err := e.topics()
+	if err != nil {
+		return err
+	}
+
+	if suggestions := Suggest(cmd.Topic(), topics, minMatch); len(suggestions) > 0 {
+		fmt.Fprintf(os.Stderr, "did you mean: %v?\n", strings.Join(suggestions, ", "))
+	}
+	return nil
+}
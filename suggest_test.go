@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"git", "git", 1},
+		{"", "", 1},
+		{"gti", "git", 2.0 / 3},
+		{"dokcer", "docker", 5.0 / 6},
+		{"kubctl", "kubectl", 6.0 / 7},
+		{"tar", "zip", 0},
+		{"", "git", 0},
+		{"über", "uber", 3.0 / 4},
+	}
+
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestMinMatch(t *testing.T) {
+	topics := []string{"docker", "docker-compose", "git", "gitk", "tar"}
+	tests := []struct {
+		topic    string
+		minMatch float64
+		want     []string
+	}{
+		{"dokcer", DefaultMinMatch, []string{"docker"}},
+		{"gti", DefaultMinMatch, []string{"git"}},
+		{"gti", 0.5, []string{"git", "gitk"}},
+		{"gti", 0.7, nil},
+		{"kubectl", DefaultMinMatch, nil},
+		{"git", 0, []string{"gitk", "docker", "docker-compose", "tar"}},
+		{"git", 1, nil},
+	}
+
+	for _, tt := range tests {
+		if got := Suggest(tt.topic, topics, tt.minMatch); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q, %v) = %q, want %q", tt.topic, tt.minMatch, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestInvalidMinMatch(t *testing.T) {
+	e := NewExecutor(testConfig(t), WithTldr(&fakeTldr{}))
+	for _, val := range []string{"-0.1", "1.5", "high"} {
+		cmd := NewCommand(CmdFind, WithArgs([]string{"gti"}), WithFlag(MinMatchFlag, val))
+		if err := e.suggest(cmd); err == nil {
+			t.Errorf("--min-match %v succeeded, want an error", val)
+		}
+	}
+}
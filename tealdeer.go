@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	LayoutTldr     = "tldr"
+	LayoutTealdeer = "tealdeer"
+)
+
+// DefaultTldrNotFoundCode is the exit code of the tldr client for pages it
+// doesn't find, unlike tealdeer.
+const (
+	DefaultTldrNotFoundCode  = 3
+	tealdeerNotFoundExitCode = 1
+)
+
+// TealdeerCachePaths returns the page directories tealdeer may cache pages
+// in, by version, under $TEALDEER_CACHE_DIR or the cache directory of the OS.
+func TealdeerCachePaths(home string) []string {
+	var roots []string
+	if dir := os.Getenv("TEALDEER_CACHE_DIR"); dir != "" {
+		roots = append(roots, dir)
+	}
+
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		roots = append(roots, filepath.Join(dir, "tealdeer"))
+	}
+	roots = append(roots, filepath.Join(home, ".cache", "tealdeer"))
+	if runtime.GOOS == "darwin" {
+		roots = append(roots, filepath.Join(home, "Library", "Caches", "tealdeer"))
+	}
+
+	var paths []string
+	for _, root := range roots {
+		paths = append(paths,
+			filepath.Join(root, "tldr-pages", "pages.en"),
+			filepath.Join(root, "tldr-pages", "pages"),
+			filepath.Join(root, "tldr-master", "pages"),
+		)
+	}
+	return paths
+}
+
+// detectCacheLayout switches to the tealdeer cache and its not-found exit
+// code when the tldr cache doesn't exist but a tealdeer one does.
+func (c *Config) detectCacheLayout(home string) {
+	c.CacheLayout = LayoutTldr
+	if isDir(c.TldrCachePath) {
+		return
+	}
+
+	for _, path := range TealdeerCachePaths(home) {
+		if isDir(path) {
+			c.CacheLayout = LayoutTealdeer
+			c.TldrCachePath = path
+			c.TldrNotFoundCode = tealdeerNotFoundExitCode
+			return
+		}
+	}
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
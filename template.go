@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// templateStarter is the content of a template created by `--edit-template`.
+// tldr placeholders are written `{{"{{"}}path}}` as `{{` starts an action.
+const templateStarter = `# {{.Topic}}
+
+> Short description of {{.Topic}}.
+
+- Example description:
+
+` + "`{{.Topic}} {{\"{{\"}}argument}}`" + `
+`
+
+// TemplateData is the data of the templates of new cheat-sheets, e.g.
+// `# {{.Topic}}`.
+type TemplateData struct {
+	Topic string
+}
+
+// TemplatePath returns the template of new cheat-sheets of the name, the
+// default one when empty, e.g. `template.md` or `template.cli.md` of the
+// cheat-sheets directory. These files aren't cheat-sheets themselves.
+func (c *Config) TemplatePath(name string) (string, error) {
+	if strings.ContainsAny(name, `/\.`) {
+		return "", fmt.Errorf("invalid template name '%v'", name)
+	}
+
+	filename := "template." + c.Extension
+	if name != "" {
+		filename = "template." + name + "." + c.Extension
+	}
+	return filepath.Join(c.CheatSheetsDir, filename), nil
+}
+
+// ParseTemplate parses the template file.
+func ParseTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse template '%v': %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// EditTemplate opens the template of new cheat-sheets of the name given, or
+// the default one, in the editor, creating it from a starter first if
+// needed, then checks it still parses.
+func (e *Executor) EditTemplate(cmd *Command) error {
+	var name string
+	if len(cmd.Args) > 0 {
+		name = cmd.Args[0]
+	}
+
+	path, err := e.cfg.TemplatePath(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := os.WriteFile(path, []byte(templateStarter), e.cfg.FilePerm()); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if err := e.editFile(cmd, e.cfg.EditorFor(path), path); err != nil {
+		return err
+	}
+
+	if _, err := ParseTemplate(path); err != nil {
+		return fmt.Errorf("%w, fix it with 'cs --edit-template %v'", err, name)
+	}
+	return nil
+}
+
+// editNewSheet edits a new cheat-sheet seeded with the content, removing it
+// when left as is so that giving up doesn't leave a bare template behind.
+func (e *Executor) editNewSheet(cmd *Command, path string, content []byte) error {
+	if err := WriteFileAtomic(path, content, e.cfg.FilePerm()); err != nil {
+		return err
+	}
+
+	if err := e.editLocalCheatSheet(cmd, path); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(data, content) {
+		return err
+	}
+
+	fmt.Printf("cheat-sheet '%v' left as the template, not created\n", cmd.Topic())
+	return os.Remove(path)
+}
+
+// newSheetContent executes the template of the command, named with
+// `--template` or else the default one, for a new cheat-sheet of its topic.
+// Without a default template, a new cheat-sheet starts empty.
+func (e *Executor) newSheetContent(cmd *Command) ([]byte, error) {
+	name := cmd.Flags[TemplateFlag]
+	path, err := e.cfg.TemplatePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := ParseTemplate(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if name != "" {
+			return nil, fmt.Errorf("no template '%v', create it with 'cs --edit-template %v'", name, name)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.PrintLog() {
+		log.Printf("seed new cheat-sheet from template '%v'\n", path)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, TemplateData{Topic: cmd.Topic()}); err != nil {
+		return nil, fmt.Errorf("execute template '%v': %w", path, err)
+	}
+	return []byte(b.String()), nil
+}
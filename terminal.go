@@ -0,0 +1,155 @@
+package main
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+const defaultTerminalWidth = 80
+
+// TerminalWidth returns the width of the terminal attached to stdout, or a
+// sensible default when stdout isn't a terminal.
+func TerminalWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 {
+		return defaultTerminalWidth
+	}
+	return width
+}
+
+// Truncate shortens s to at most width runes, marking the cut with "...".
+func Truncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+
+	if width <= 3 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-3]) + "..."
+}
+
+const (
+	hideCursor = "\x1b[?25l"
+	showCursor = "\x1b[?25h"
+)
+
+// terminalRestorers undo changes to the terminal, e.g. raw mode, when the
+// tool exits without returning from the command changing it.
+var terminalRestorers struct {
+	sync.Mutex
+	next int
+	fns  map[int]func()
+}
+
+// restoreOnExit registers restore to run by RestoreTerminals, returning a
+// func unregistering it once the terminal is restored.
+func restoreOnExit(restore func()) func() {
+	terminalRestorers.Lock()
+	defer terminalRestorers.Unlock()
+	if terminalRestorers.fns == nil {
+		terminalRestorers.fns = make(map[int]func())
+	}
+
+	id := terminalRestorers.next
+	terminalRestorers.next++
+	terminalRestorers.fns[id] = restore
+	return func() {
+		terminalRestorers.Lock()
+		defer terminalRestorers.Unlock()
+		delete(terminalRestorers.fns, id)
+	}
+}
+
+// RestoreTerminals restores terminals left changed, before exiting.
+func RestoreTerminals() {
+	terminalRestorers.Lock()
+	defer terminalRestorers.Unlock()
+	for _, restore := range terminalRestorers.fns {
+		restore()
+	}
+}
+
+// TerminalController changes the mode of terminals and restores it.
+type TerminalController interface {
+	MakeRaw(fd int) (*term.State, error)
+	GetState(fd int) (*term.State, error)
+	Restore(fd int, state *term.State) error
+}
+
+// xTerminals controls terminals with golang.org/x/term.
+type xTerminals struct{}
+
+func (xTerminals) MakeRaw(fd int) (*term.State, error) {
+	return term.MakeRaw(fd)
+}
+
+func (xTerminals) GetState(fd int) (*term.State, error) {
+	return term.GetState(fd)
+}
+
+func (xTerminals) Restore(fd int, state *term.State) error {
+	return term.Restore(fd, state)
+}
+
+// terminals controls the terminals of the tool, faked in tests.
+var terminals TerminalController = xTerminals{}
+
+// RawTerminal is a terminal in raw mode with the cursor hidden until
+// restored, also when the tool exits once interrupted.
+type RawTerminal struct {
+	fd         int
+	out        io.Writer
+	state      *term.State
+	once       sync.Once
+	unregister func()
+}
+
+func MakeRawTerminal(fd int, out io.Writer) (*RawTerminal, error) {
+	state, err := terminals.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &RawTerminal{fd: fd, out: out, state: state}
+	t.unregister = restoreOnExit(t.restore)
+	io.WriteString(out, hideCursor)
+	return t, nil
+}
+
+// Restore shows the cursor and restores the previous mode. Only the first
+// call does anything.
+func (t *RawTerminal) Restore() {
+	t.unregister()
+	t.restore()
+}
+
+func (t *RawTerminal) restore() {
+	t.once.Do(func() {
+		io.WriteString(t.out, showCursor)
+		terminals.Restore(t.fd, t.state)
+	})
+}
+
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// UseColor decides whether to color output for the color mode, `auto`
+// coloring only when stdout is a terminal and NO_COLOR isn't set.
+func UseColor(mode string) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	default:
+		return os.Getenv("NO_COLOR") == "" && term.IsTerminal(int(os.Stdout.Fd()))
+	}
+}
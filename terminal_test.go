@@ -0,0 +1,145 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/term"
+)
+
+// fakeTerminals is a TerminalController recording the terminals it makes raw
+// and restores.
+type fakeTerminals struct {
+	rawErr   error
+	raw      []int
+	restored []int
+}
+
+func (f *fakeTerminals) MakeRaw(fd int) (*term.State, error) {
+	if f.rawErr != nil {
+		return nil, f.rawErr
+	}
+	f.raw = append(f.raw, fd)
+	return &term.State{}, nil
+}
+
+func (f *fakeTerminals) GetState(fd int) (*term.State, error) {
+	return &term.State{}, nil
+}
+
+func (f *fakeTerminals) Restore(fd int, state *term.State) error {
+	f.restored = append(f.restored, fd)
+	return nil
+}
+
+func fakeTerminalController(t *testing.T) *fakeTerminals {
+	t.Helper()
+	fake := &fakeTerminals{}
+	saved := terminals
+	terminals = fake
+	t.Cleanup(func() { terminals = saved })
+	return fake
+}
+
+func TestRawTerminalRestoredOnError(t *testing.T) {
+	fake := fakeTerminalController(t)
+	var out strings.Builder
+
+	errPick := errors.New("pick failed")
+	err := func() error {
+		raw, err := MakeRawTerminal(7, &out)
+		if err != nil {
+			return err
+		}
+		defer raw.Restore()
+		return errPick
+	}()
+
+	if !errors.Is(err, errPick) {
+		t.Fatalf("got %v, want %v", err, errPick)
+	}
+
+	if len(fake.restored) != 1 || fake.restored[0] != 7 {
+		t.Errorf("restored %v, want [7]", fake.restored)
+	}
+
+	if got := out.String(); got != hideCursor+showCursor {
+		t.Errorf("wrote %q, want the cursor hidden then shown", got)
+	}
+}
+
+func TestRawTerminalRestoredOnPanic(t *testing.T) {
+	fake := fakeTerminalController(t)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("want a panic")
+			}
+		}()
+
+		raw, err := MakeRawTerminal(7, &strings.Builder{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer raw.Restore()
+		panic("boom")
+	}()
+
+	if len(fake.restored) != 1 {
+		t.Errorf("restored %v, want once", fake.restored)
+	}
+}
+
+func TestRawTerminalRestoredOnSignal(t *testing.T) {
+	fake := fakeTerminalController(t)
+
+	raw, err := MakeRawTerminal(7, &strings.Builder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// As done by exitOnInterrupt before exiting.
+	RestoreTerminals()
+	if len(fake.restored) != 1 {
+		t.Fatalf("restored %v once interrupted, want once", fake.restored)
+	}
+
+	// Returning afterwards doesn't restore again.
+	raw.Restore()
+	RestoreTerminals()
+	if len(fake.restored) != 1 {
+		t.Errorf("restored %v, want once", fake.restored)
+	}
+}
+
+func TestRestoredTerminalUnregistered(t *testing.T) {
+	fake := fakeTerminalController(t)
+
+	raw, err := MakeRawTerminal(7, &strings.Builder{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw.Restore()
+
+	RestoreTerminals()
+	if len(fake.restored) != 1 {
+		t.Errorf("restored %v, want once", fake.restored)
+	}
+}
+
+func TestMakeRawTerminalFails(t *testing.T) {
+	fake := fakeTerminalController(t)
+	fake.rawErr = errors.New("not a terminal")
+	var out strings.Builder
+
+	if _, err := MakeRawTerminal(7, &out); !errors.Is(err, fake.rawErr) {
+		t.Fatalf("got %v, want %v", err, fake.rawErr)
+	}
+
+	RestoreTerminals()
+	if len(fake.restored) != 0 || out.Len() != 0 {
+		t.Errorf("restored %v and wrote %q, want nothing", fake.restored, out.String())
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Elements of a cheat-sheet colored by the native renderer.
+const (
+	ElemTitle       = "title"
+	ElemDescription = "description"
+	ElemExample     = "example"
+	ElemCode        = "code"
+	ElemPlaceholder = "placeholder"
+)
+
+// Theme maps elements of a cheat-sheet to ANSI SGR parameters, e.g. "1;34".
+type Theme map[string]string
+
+var builtinThemes = map[string]Theme{
+	"default": {
+		ElemTitle:       "1",
+		ElemDescription: "",
+		ElemExample:     "32",
+		ElemCode:        "31",
+		ElemPlaceholder: "34",
+	},
+	"mono": {
+		ElemTitle:       "1",
+		ElemDescription: "",
+		ElemExample:     "",
+		ElemCode:        "1",
+		ElemPlaceholder: "4",
+	},
+	"solarized": {
+		ElemTitle:       "1;38;5;33",
+		ElemDescription: "38;5;245",
+		ElemExample:     "38;5;64",
+		ElemCode:        "38;5;37",
+		ElemPlaceholder: "38;5;136",
+	},
+}
+
+var namedColors = map[string]string{
+	"bold":           "1",
+	"dim":            "2",
+	"italic":         "3",
+	"underline":      "4",
+	"black":          "30",
+	"red":            "31",
+	"green":          "32",
+	"yellow":         "33",
+	"blue":           "34",
+	"magenta":        "35",
+	"cyan":           "36",
+	"white":          "37",
+	"gray":           "90",
+	"bright-red":     "91",
+	"bright-green":   "92",
+	"bright-yellow":  "93",
+	"bright-blue":    "94",
+	"bright-magenta": "95",
+	"bright-cyan":    "96",
+	"bright-white":   "97",
+}
+
+// ThemeConfig selects a built-in theme and overrides some of its colors
+// with named colors, e.g. "bold blue", or raw SGR parameters, e.g. "1;34".
+type ThemeConfig struct {
+	Base        string `yaml:"base" toml:"base"`
+	Title       string `yaml:"title,omitempty" toml:"title,omitempty"`
+	Description string `yaml:"description,omitempty" toml:"description,omitempty"`
+	Example     string `yaml:"example,omitempty" toml:"example,omitempty"`
+	Code        string `yaml:"code,omitempty" toml:"code,omitempty"`
+	Placeholder string `yaml:"placeholder,omitempty" toml:"placeholder,omitempty"`
+}
+
+// ThemeNames returns the names of the built-in themes.
+func ThemeNames() []string {
+	var names []string
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewTheme builds the theme of the config. Unknown themes and invalid
+// colors fall back to the default ones with a warning.
+func NewTheme(cfg ThemeConfig) Theme {
+	base, ok := builtinThemes[cfg.Base]
+	if !ok {
+		if cfg.Base != "" {
+			warnf("unknown theme '%v', use one of %v", cfg.Base, strings.Join(ThemeNames(), ", "))
+		}
+		base = builtinThemes["default"]
+	}
+
+	theme := make(Theme, len(base))
+	for elem, code := range base {
+		theme[elem] = code
+	}
+
+	overrides := map[string]string{
+		ElemTitle:       cfg.Title,
+		ElemDescription: cfg.Description,
+		ElemExample:     cfg.Example,
+		ElemCode:        cfg.Code,
+		ElemPlaceholder: cfg.Placeholder,
+	}
+	for elem, color := range overrides {
+		if color == "" {
+			continue
+		}
+
+		code, err := ParseColor(color)
+		if err != nil {
+			warnf("invalid %v color: %v", elem, err)
+			continue
+		}
+		theme[elem] = code
+	}
+
+	return theme
+}
+
+// ParseColor converts space separated named colors or SGR parameters to
+// SGR parameters.
+func ParseColor(color string) (string, error) {
+	var codes []string
+	for _, word := range strings.Fields(color) {
+		if code, ok := namedColors[strings.ToLower(word)]; ok {
+			codes = append(codes, code)
+			continue
+		}
+
+		for _, param := range strings.Split(word, ";") {
+			if n, err := strconv.Atoi(param); err != nil || n < 0 || n > 255 {
+				return "", fmt.Errorf("'%v' is neither a named color nor an ANSI code", word)
+			}
+		}
+		codes = append(codes, word)
+	}
+
+	return strings.Join(codes, ";"), nil
+}
+
+// Paint wraps s in the escape sequences of the element color.
+func (t Theme) Paint(elem, s string) string {
+	code := t[elem]
+	if code == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+func warnf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "warning: "+format+"\n", args...)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ReadTopicsFile reads topics, one per line, skipping blank lines and `#`
+// comments.
+func ReadTopicsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var topics []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			topics = append(topics, line)
+		}
+	}
+	return topics, scanner.Err()
+}
+
+// selectedSheets returns the cheat-sheets of the topics file of the command
+// in its order, local or else cached, with the topics found nowhere. Without
+// a topics file, it returns the local cheat-sheets.
+func (e *Executor) selectedSheets(cmd *Command) ([]CheatSheet, []string, error) {
+	topicsFile := cmd.Flags[TopicsFileFlag]
+	if topicsFile == "" {
+		sheets, err := LocalCheatSheets(e.cfg)
+		return sheets, nil, err
+	}
+
+	topics, err := ReadTopicsFile(topicsFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sheets []CheatSheet
+	var missing []string
+	for _, topic := range topics {
+		path, err := e.findLocalTopic(topic)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		local := path != ""
+		if !local {
+			if path, err = e.findCachedTopic(topic); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if path == "" {
+			missing = append(missing, topic)
+			continue
+		}
+		sheets = append(sheets, CheatSheet{Topic: topic, Path: path, Local: local})
+	}
+	return sheets, missing, nil
+}
+
+// Topics prints the names of all topics, local and of the tldr cache, sorted
+// and deduplicated, one per line with nothing else. Completion scripts and
+// pickers rely on it, so its output doesn't change.
+func (e *Executor) Topics() error {
+	topics, err := e.topics()
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	for _, topic := range topics {
+		fmt.Fprintln(out, topic)
+	}
+	return out.Flush()
+}
+
+// topics returns the topics of the local cheat-sheets and of the pages of the
+// tldr cache, sorted and once each. They're read from the topic index kept by
+// WatchDir while it's fresh, else the directories are scanned.
+func (e *Executor) topics() ([]string, error) {
+	if topics := e.indexedTopics(); topics != nil {
+		return topics, nil
+	}
+	return e.scanTopics()
+}
+
+// indexedTopics returns the topics of the topic index, or nil if there is
+// none or it may be stale: older than the cheat-sheets directory, its ignore
+// file, the config or a page directory of the tldr cache, as topics are
+// added or removed by changing one of them.
+func (e *Executor) indexedTopics() []string {
+	path := filepath.Join(e.cfg.DataDir, topicIndexFilename)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	sources := []string{e.cfg.CheatSheetsDir, filepath.Join(e.cfg.CheatSheetsDir, IgnoreFilename), e.cfg.Path}
+	for _, page := range e.cfg.TldrPages {
+		sources = append(sources, filepath.Join(e.cfg.TldrCachePath, page))
+	}
+
+	for _, source := range sources {
+		if source == "" {
+			continue
+		}
+
+		sourceInfo, err := os.Stat(source)
+		if err == nil && sourceInfo.ModTime().After(info.ModTime()) {
+			return nil
+		}
+	}
+
+	topics, err := ReadTopicsFile(path)
+	if err != nil || len(topics) == 0 {
+		return nil
+	}
+	return topics
+}
+
+// scanTopics returns the topics of the local cheat-sheets and of the pages of
+// the tldr cache, listing their directories.
+func (e *Executor) scanTopics() ([]string, error) {
+	local, err := LocalCheatSheets(e.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, err := CachedCheatSheets(e.cfg.TldrCachePath, e.cfg.TldrPages)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var topics []string
+	for _, sheet := range append(local, cached...) {
+		if !seen[sheet.Topic] {
+			seen[sheet.Topic] = true
+			topics = append(topics, sheet.Topic)
+		}
+	}
+
+	sort.Strings(topics)
+	return topics, nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// UpstreamURL is the base URL of the sources of the tldr pages, laid out like
+// the directory holding the tldr cache: pages/<set>/<topic>.md, with
+// translations under pages.<lang>.
+const UpstreamURL = "https://github.com/tldr-pages/tldr/blob/main/"
+
+var ErrNoBrowser = errors.New("no browser opener found, install xdg-utils or print the url with --print-url")
+
+// PageURL returns the upstream URL of the page at path of the tldr cache.
+func PageURL(cachePath, path string) (string, error) {
+	rel, err := filepath.Rel(filepath.Dir(cachePath), path)
+	if err != nil {
+		return "", err
+	}
+
+	// The english pages of tealdeer are `pages.en`, upstream `pages`.
+	rel = strings.Replace(filepath.ToSlash(rel), "pages.en/", "pages/", 1)
+	if !strings.HasPrefix(rel, "pages") {
+		return "", fmt.Errorf("'%v' isn't a page of the tldr cache '%v'", path, cachePath)
+	}
+	return UpstreamURL + rel, nil
+}
+
+// OpenURLCommand returns the command line opening its last argument in the
+// browser, picked from the tools available on this OS.
+func OpenURLCommand() ([]string, error) {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"open"}}
+	case "windows":
+		candidates = [][]string{{"rundll32", "url.dll,FileProtocolHandler"}}
+	default:
+		candidates = [][]string{{"xdg-open"}, {"wslview"}}
+	}
+
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err == nil {
+			return candidate, nil
+		}
+	}
+	return nil, ErrNoBrowser
+}
+
+// OpenURL resolves the tldr cache page of the topic, in the --lang language
+// if given, and opens its upstream source in the browser, or prints its URL.
+func (e *Executor) OpenURL(cmd *Command) error {
+	filename := NormalizeTopic(cmd.Topic()) + "." + DefaultExtension
+	var path string
+	var err error
+	if lang := cmd.Flags[LangFlag]; lang != "" && lang != "en" {
+		path, err = e.tldr.FindLocalizedFileInCache(lang, filename)
+	} else {
+		path, err = e.tldr.FindFileInCache(filename)
+	}
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return fmt.Errorf("%w: '%v' in tldr cache", ErrNotFound, cmd.Topic())
+	}
+
+	url, err := PageURL(e.cfg.TldrCachePath, path)
+	if err != nil {
+		return err
+	}
+
+	if cmd.HasFlag(PrintURLFlag) {
+		fmt.Println(url)
+		return nil
+	}
+
+	args, err := OpenURLCommand()
+	if err != nil {
+		return err
+	}
+
+	if cmd.PrintLog() {
+		log.Printf("open '%v' with %v\n", url, args[0])
+	}
+	return exec.CommandContext(e.ctx, args[0], append(args[1:], url)...).Run()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const usageFilename = "usage.jsonl"
+
+// usageRecord is a line of the usage log, kept short as the log grows with
+// every lookup.
+type usageRecord struct {
+	Topic string `json:"t"`
+	At    int64  `json:"at"`
+}
+
+type TopicUsage struct {
+	Topic    string
+	Count    int
+	LastUsed time.Time
+}
+
+func usagePath(cfg *Config) string {
+	return filepath.Join(cfg.DataDir, usageFilename)
+}
+
+// RecordUsage appends an access of the topic to the usage log.
+func RecordUsage(cfg *Config, topic string, at time.Time) error {
+	data, err := json.Marshal(usageRecord{Topic: topic, At: at.Unix()})
+	if err != nil {
+		return err
+	}
+	return cfg.AppendState(usagePath(cfg), data)
+}
+
+// LoadUsage aggregates the usage log per topic, most used first. A missing
+// log is empty and corrupt lines are skipped.
+func LoadUsage(cfg *Config) ([]TopicUsage, error) {
+	f, err := os.Open(usagePath(cfg))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	byTopic := make(map[string]*TopicUsage)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record usageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.Topic == "" {
+			continue
+		}
+
+		usage, ok := byTopic[record.Topic]
+		if !ok {
+			usage = &TopicUsage{Topic: record.Topic}
+			byTopic[record.Topic] = usage
+		}
+
+		usage.Count++
+		if at := time.Unix(record.At, 0); at.After(usage.LastUsed) {
+			usage.LastUsed = at
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	usages := make([]TopicUsage, 0, len(byTopic))
+	for _, usage := range byTopic {
+		usages = append(usages, *usage)
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Count != usages[j].Count {
+			return usages[i].Count > usages[j].Count
+		}
+		return usages[i].Topic < usages[j].Topic
+	})
+	return usages, nil
+}
+
+// MostRecentTopic returns the topic of the usages last used, if any.
+func MostRecentTopic(usages []TopicUsage) string {
+	var mru TopicUsage
+	for _, usage := range usages {
+		if usage.LastUsed.After(mru.LastUsed) {
+			mru = usage
+		}
+	}
+	return mru.Topic
+}
+
+func ResetUsage(cfg *Config) error {
+	err := os.Remove(usagePath(cfg))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
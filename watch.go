@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// WatchUpdate updates the tldr cache every interval until interrupted. It's
+// a long running foreground process; updates overlapping one started
+// elsewhere are skipped.
+func (e *Executor) WatchUpdate(cmd *Command) error {
+	interval, err := time.ParseDuration(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid interval: %w", err)
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval: '%v' must be positive", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("updating tldr cache every %v\n", interval)
+	for {
+		e.watchedUpdate()
+
+		select {
+		case <-e.ctx.Done():
+			log.Println("stop updating tldr cache")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Executor) watchedUpdate() {
+	lock := NewFileLock(e.cfg, filepath.Join(e.cfg.DataDir, updateLockFilename))
+	ok, err := lock.TryLock()
+	if err != nil {
+		log.Printf("update failed: %v\n", err)
+		return
+	}
+
+	if !ok {
+		log.Println("another update is running, skip")
+		return
+	}
+	defer lock.Unlock()
+
+	start := time.Now()
+	if err := e.tldr.Update(e.ctx); err != nil {
+		log.Printf("update failed: %v\n", err)
+		return
+	}
+	log.Printf("updated in %v\n", time.Since(start).Round(time.Millisecond))
+}
+
+// topicIndexFilename is the index of topics, local ones and those of the
+// tldr cache, kept by WatchDir one per line. While fresh, it's read instead of
+// scanning the directories, e.g. by `--topics` for shell completion.
+const topicIndexFilename = "topics"
+
+// WatchDir polls the cheat-sheets directory every interval until
+// interrupted, rewriting the topic index whenever cheat-sheets are added,
+// removed or changed, e.g. by an IDE.
+func (e *Executor) WatchDir(cmd *Command) error {
+	interval, err := time.ParseDuration(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid interval: %w", err)
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval: '%v' must be positive", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	path := filepath.Join(e.cfg.DataDir, topicIndexFilename)
+	log.Printf("indexing '%v' into '%v' every %v\n", e.cfg.CheatSheetsDir, path, interval)
+	var last string
+	for {
+		if state, err := e.dirState(); err != nil {
+			log.Printf("index failed: %v\n", err)
+		} else if state != last {
+			if err := e.writeTopicIndex(path); err != nil {
+				log.Printf("index failed: %v\n", err)
+			} else {
+				last = state
+				log.Println("indexed topics")
+			}
+		}
+
+		select {
+		case <-e.ctx.Done():
+			log.Println("stop indexing topics")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// dirState sums up the local cheat-sheets by name, size and modification
+// time, and the page directories of the tldr cache by modification time, so
+// that any change shows as a different state.
+func (e *Executor) dirState() (string, error) {
+	sheets, err := LocalCheatSheets(e.cfg)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, page := range e.cfg.TldrPages {
+		if info, err := os.Stat(filepath.Join(e.cfg.TldrCachePath, page)); err == nil {
+			fmt.Fprintf(&b, "%v\t%d\n", page, info.ModTime().UnixNano())
+		}
+	}
+
+	for _, sheet := range sheets {
+		info, err := os.Stat(sheet.Path)
+		if err != nil {
+			// Removed since listed, the next poll sees it.
+			continue
+		}
+		fmt.Fprintf(&b, "%v\t%d\t%d\n", sheet.Path, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String(), nil
+}
+
+func (e *Executor) writeTopicIndex(path string) error {
+	topics, err := e.scanTopics()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for _, topic := range topics {
+		b.WriteString(topic + "\n")
+	}
+	return e.cfg.WriteState(path, []byte(b.String()))
+}
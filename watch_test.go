@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTopicsReadFreshIndex(t *testing.T) {
+	cfg := testConfig(t)
+	writeSheet(t, cfg, "git", "# git\n")
+	e := NewExecutor(cfg, WithTldr(&fakeTldr{}))
+
+	index := filepath.Join(cfg.DataDir, topicIndexFilename)
+	if err := e.writeTopicIndex(index); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tell the index apart from a scan, as if written by a watcher.
+	if err := os.WriteFile(index, []byte("git\nindexed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(index, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	topics, err := e.topics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"git", "indexed"}; !reflect.DeepEqual(topics, want) {
+		t.Errorf("topics of a fresh index %v, want %v", topics, want)
+	}
+
+	// Adding a cheat-sheet makes the index stale until rewritten.
+	writeSheet(t, cfg, "tar", "# tar\n")
+	evenLater := later.Add(time.Hour)
+	if err := os.Chtimes(cfg.CheatSheetsDir, evenLater, evenLater); err != nil {
+		t.Fatal(err)
+	}
+
+	topics, err = e.topics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"git", "tar"}; !reflect.DeepEqual(topics, want) {
+		t.Errorf("topics of a stale index %v, want %v", topics, want)
+	}
+}